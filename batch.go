@@ -0,0 +1,151 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// resultCodeKeyNotFound is the Aerospike KEY_NOT_FOUND_ERROR result code.
+const resultCodeKeyNotFound = 2
+
+// BatchRecordError describes the failure of a single batch record.
+type BatchRecordError struct {
+	// Index is the position of the record in the batch.
+	Index int
+	// ResultCode is the Aerospike result code of the record.
+	ResultCode int
+	// InDoubt signifies that the write command may have completed even though
+	// an error occurred.
+	InDoubt bool
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BatchRecordError) Error() string {
+	return fmt.Sprintf("batch record %d (result code %d, in doubt %t): %v",
+		e.Index, e.ResultCode, e.InDoubt, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchRecordError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeBatch decodes a slice of batch results (e.g. BatchRecord or BatchRead elements
+// or pointers to them) into out, which must be a pointer to a []T or []*T slice.
+//
+// The returned errors slice is positional, with the same length as the batch.
+// A record that was not found, failed on the server, or could not be decoded results
+// in a *BatchRecordError at its position, and a zero value (or nil) entry in out.
+// The non-nil error is returned only if the arguments are invalid.
+func DecodeBatch(records any, out any) ([]error, error) {
+	return defaultMapper.DecodeBatch(records, out)
+}
+
+// DecodeBatch decodes a slice of batch results into out using the Mapper configuration.
+// See the package level DecodeBatch function for details.
+func (m *Mapper) DecodeBatch(records any, out any) ([]error, error) {
+	recordsValue := reflect.ValueOf(records)
+	if recordsValue.Kind() == reflect.Ptr {
+		recordsValue = recordsValue.Elem()
+	}
+	if recordsValue.Kind() != reflect.Slice && recordsValue.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: records must be a slice", ErrInvalidSourceType)
+	}
+
+	outValue := reflect.ValueOf(out)
+	if outValue.Kind() != reflect.Ptr || outValue.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w: out must be a pointer to a slice", ErrInvalidTargetType)
+	}
+
+	// validate the slice element type
+	elemType := outValue.Elem().Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: unsupported slice element type %s",
+			ErrInvalidTargetType, elemType)
+	}
+
+	n := recordsValue.Len()
+	errs := make([]error, n)
+	decoded := reflect.MakeSlice(outValue.Elem().Type(), n, n)
+	for i := 0; i < n; i++ {
+		target := reflect.New(structType)
+		if err := m.decodeBatchRecord(recordsValue.Index(i), target.Interface()); err != nil {
+			err.Index = i
+			errs[i] = err
+			continue
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			decoded.Index(i).Set(target)
+		} else {
+			decoded.Index(i).Set(target.Elem())
+		}
+	}
+
+	outValue.Elem().Set(decoded)
+	return errs, nil
+}
+
+// decodeBatchRecord decodes a single batch record into v.
+func (m *Mapper) decodeBatchRecord(batchRecord reflect.Value, v any) *BatchRecordError {
+	for batchRecord.Kind() == reflect.Interface || batchRecord.Kind() == reflect.Ptr {
+		if batchRecord.IsNil() {
+			return &BatchRecordError{
+				ResultCode: resultCodeKeyNotFound,
+				Err:        ErrRecordNotFound,
+			}
+		}
+		batchRecord = batchRecord.Elem()
+	}
+	if batchRecord.Kind() != reflect.Struct {
+		return &BatchRecordError{Err: ErrInvalidSourceType}
+	}
+
+	batchErr := &BatchRecordError{}
+	if resultCode, err := getField(batchRecord, "ResultCode"); err == nil &&
+		resultCode.CanInt() {
+		batchErr.ResultCode = int(resultCode.Int())
+	}
+	if inDoubt, err := getField(batchRecord, "InDoubt"); err == nil &&
+		inDoubt.Kind() == reflect.Bool {
+		batchErr.InDoubt = inDoubt.Bool()
+	}
+	if errValue, err := getField(batchRecord, "Err"); err == nil &&
+		errValue.Kind() == reflect.Interface && !errValue.IsNil() {
+		if recordErr, ok := errValue.Interface().(error); ok {
+			if batchErr.ResultCode == resultCodeKeyNotFound {
+				batchErr.Err = fmt.Errorf("%w: %v", ErrRecordNotFound, recordErr)
+			} else {
+				batchErr.Err = recordErr
+			}
+			return batchErr
+		}
+	}
+
+	record, err := getField(batchRecord, "Record")
+	if err != nil {
+		batchErr.Err = ErrInvalidSource
+		return batchErr
+	}
+	if record.Kind() == reflect.Ptr && record.IsNil() {
+		if batchErr.ResultCode == 0 || batchErr.ResultCode == resultCodeKeyNotFound {
+			batchErr.Err = ErrRecordNotFound
+		} else {
+			batchErr.Err = fmt.Errorf("result code %d", batchErr.ResultCode)
+		}
+		return batchErr
+	}
+
+	if err := m.Decode(batchRecord.Interface(), v); err != nil {
+		batchErr.Err = err
+		return batchErr
+	}
+
+	return nil
+}
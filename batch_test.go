@@ -0,0 +1,91 @@
+package mapper_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_DecodeBatch(t *testing.T) {
+	record1, err := newTestRecord()
+	assert.IsNil(t, err)
+
+	errTimeout := errors.New("timeout")
+	errKeyNotFound := errors.New("key not found")
+	invalidRecord := &testtypes.Record{
+		Key:  record1.Key,
+		Bins: testtypes.BinMap{"length": "invalid"},
+	}
+	records := []*testtypes.BatchRead{
+		{BatchRecord: testtypes.BatchRecord{Record: record1}},
+		{BatchRecord: testtypes.BatchRecord{ResultCode: 2}},
+		{BatchRecord: testtypes.BatchRecord{ResultCode: 9, Err: errTimeout, InDoubt: true}},
+		nil,
+		{BatchRecord: testtypes.BatchRecord{Record: invalidRecord}},
+		{BatchRecord: testtypes.BatchRecord{ResultCode: 2, Err: errKeyNotFound}},
+	}
+
+	assertErrors := func(t *testing.T, errs []error) {
+		t.Helper()
+		assert.Equal(t, len(errs), len(records))
+		assert.IsNil(t, errs[0])
+		assert.ErrorIs(t, errs[1], mapper.ErrRecordNotFound)
+		assert.ErrorIs(t, errs[2], errTimeout)
+		assert.ErrorIs(t, errs[3], mapper.ErrRecordNotFound)
+		assert.ErrorIs(t, errs[4], strconv.ErrSyntax)
+		assert.ErrorIs(t, errs[5], mapper.ErrRecordNotFound)
+		assert.Equal(t, strings.Contains(errs[5].Error(), errKeyNotFound.Error()), true)
+
+		var batchErr *mapper.BatchRecordError
+		assert.Equal(t, errors.As(errs[2], &batchErr), true)
+		assert.Equal(t, *batchErr, mapper.BatchRecordError{
+			Index:      2,
+			ResultCode: 9,
+			InDoubt:    true,
+			Err:        errTimeout,
+		})
+	}
+
+	t.Run("values", func(t *testing.T) {
+		var items []testtypes.Item
+		errs, err := mapper.DecodeBatch(records, &items)
+		assert.IsNil(t, err)
+		assertErrors(t, errs)
+
+		assert.Equal(t, len(items), len(records))
+		assert.Equal(t, items[0].Title, "title1")
+		assert.Equal(t, items[0].UserKey, any("key1"))
+		assert.Equal(t, items[1], testtypes.Item{})
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		var items []*testtypes.Item
+		errs, err := mapper.DecodeBatch(records, &items)
+		assert.IsNil(t, err)
+		assertErrors(t, errs)
+
+		assert.Equal(t, len(items), len(records))
+		assert.Equal(t, items[0].Title, "title1")
+		for _, item := range items[1:] {
+			assert.IsNil(t, item)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var items []testtypes.Item
+		_, err := mapper.DecodeBatch(records[0], &items)
+		assert.ErrorIs(t, err, mapper.ErrInvalidSourceType)
+
+		_, err = mapper.DecodeBatch(records, items)
+		assert.ErrorIs(t, err, mapper.ErrInvalidTargetType)
+
+		var ints []int
+		_, err = mapper.DecodeBatch(records, &ints)
+		assert.ErrorIs(t, err, mapper.ErrInvalidTargetType)
+	})
+}
@@ -78,3 +78,45 @@ func newTestRecord() (*testtypes.Record, error) {
 		Expiration: 1_000,
 	}, nil
 }
+
+func BenchmarkMapper_EncodeInto(b *testing.B) {
+	item, err := newTestItem()
+	if err != nil {
+		b.Fatal(err)
+	}
+	record := &mapper.Record{}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		_ = mapper.EncodeInto(item, record)
+	}
+}
+
+func BenchmarkMapper_EncodeRecordPool(b *testing.B) {
+	item, err := newTestItem()
+	if err != nil {
+		b.Fatal(err)
+	}
+	pool := mapper.NewRecordPool()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		record := pool.Get()
+		_ = mapper.EncodeInto(item, record)
+		pool.Put(record)
+	}
+}
+
+func newTestItem() (*testtypes.Item, error) {
+	record, err := newTestRecord()
+	if err != nil {
+		return nil, err
+	}
+	var item testtypes.Item
+	if err := mapper.Decode(record, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
@@ -0,0 +1,130 @@
+package mapper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// Cipher encrypts and decrypts the values of the fields tagged with the `encrypt`
+// option. The ciphertext must contain everything required to decrypt it, e.g. the
+// ID of the key used for encryption.
+type Cipher interface {
+	// Encrypt encrypts the plaintext and returns the ciphertext.
+	Encrypt(plaintext []byte) ([]byte, error)
+	// Decrypt decrypts the ciphertext and returns the plaintext.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// aesCipherVersion is the version of the AESCipher ciphertext format.
+const aesCipherVersion byte = 1
+
+// AESCipher is a Cipher using AES-GCM. The ciphertext is formatted as:
+//
+//	version (1 byte) | key ID length (1 byte) | key ID | nonce | sealed data
+//
+// The key ID stored with the ciphertext allows rotating the encryption key while
+// keeping the previous keys for decryption.
+type AESCipher struct {
+	keyID string
+	aeads map[string]cipher.AEAD
+}
+
+var _ Cipher = (*AESCipher)(nil)
+
+// NewAESCipher returns a new AESCipher, which encrypts using the key with the given
+// ID, and decrypts using any of the keys. The keys must be 16, 24 or 32 bytes long
+// to select AES-128, AES-192 or AES-256, and the key IDs at most 255 bytes long.
+func NewAESCipher(keyID string, keys map[string][]byte) (*AESCipher, error) {
+	if _, ok := keys[keyID]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, keyID)
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if len(id) > 255 {
+			return nil, fmt.Errorf("key ID %q is too long", id)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	return &AESCipher{
+		keyID: keyID,
+		aeads: aeads,
+	}, nil
+}
+
+// Encrypt encrypts the plaintext using the current key.
+func (c *AESCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	aead := c.aeads[c.keyID]
+	headerSize := 2 + len(c.keyID)
+
+	ciphertext := make([]byte, headerSize+aead.NonceSize(),
+		headerSize+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	ciphertext[0] = aesCipherVersion
+	ciphertext[1] = byte(len(c.keyID))
+	copy(ciphertext[2:], c.keyID)
+
+	nonce := ciphertext[headerSize:]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(ciphertext, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts the ciphertext using the key with the ID stored in it.
+func (c *AESCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != aesCipherVersion {
+		return nil, ErrInvalidCiphertext
+	}
+
+	headerSize := 2 + int(ciphertext[1])
+	if len(ciphertext) < headerSize {
+		return nil, ErrInvalidCiphertext
+	}
+
+	keyID := string(ciphertext[2:headerSize])
+	aead, ok := c.aeads[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, keyID)
+	}
+
+	if len(ciphertext) < headerSize+aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce := ciphertext[headerSize : headerSize+aead.NonceSize()]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext[headerSize+aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+
+	return plaintext, nil
+}
+
+// encrypt encrypts the data using the Mapper Cipher.
+func (m *Mapper) encrypt(data []byte) ([]byte, error) {
+	if m.cipher == nil {
+		return nil, ErrCipherNotConfigured
+	}
+	return m.cipher.Encrypt(data)
+}
+
+// decrypt decrypts the data using the Mapper Cipher.
+func (m *Mapper) decrypt(data []byte) ([]byte, error) {
+	if m.cipher == nil {
+		return nil, ErrCipherNotConfigured
+	}
+	return m.cipher.Decrypt(data)
+}
@@ -0,0 +1,150 @@
+package mapper_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+var (
+	key1 = bytes.Repeat([]byte{1}, 32)
+	key2 = bytes.Repeat([]byte{2}, 16)
+)
+
+func TestMapper_AESCipher(t *testing.T) {
+	cipher1, err := mapper.NewAESCipher("k1", map[string][]byte{"k1": key1})
+	assert.IsNil(t, err)
+
+	ciphertext, err := cipher1.Encrypt([]byte("plaintext"))
+	assert.IsNil(t, err)
+	assert.Equal(t, ciphertext[0], byte(1))
+	assert.Equal(t, string(ciphertext[2:4]), "k1")
+
+	// rotate the key, and decrypt the values encrypted using the previous key
+	cipher2, err := mapper.NewAESCipher("k2", map[string][]byte{"k1": key1, "k2": key2})
+	assert.IsNil(t, err)
+
+	plaintext, err := cipher2.Decrypt(ciphertext)
+	assert.IsNil(t, err)
+	assert.Equal(t, string(plaintext), "plaintext")
+
+	ciphertext, err = cipher2.Encrypt([]byte("plaintext"))
+	assert.IsNil(t, err)
+	assert.Equal(t, string(ciphertext[2:4]), "k2")
+
+	_, err = cipher1.Decrypt(ciphertext)
+	assert.ErrorIs(t, err, mapper.ErrUnknownKeyID)
+
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = cipher2.Decrypt(ciphertext)
+	assert.ErrorIs(t, err, mapper.ErrInvalidCiphertext)
+
+	_, err = cipher2.Decrypt([]byte{1})
+	assert.ErrorIs(t, err, mapper.ErrInvalidCiphertext)
+
+	_, err = mapper.NewAESCipher("k3", map[string][]byte{"k1": key1})
+	assert.ErrorIs(t, err, mapper.ErrUnknownKeyID)
+
+	_, err = mapper.NewAESCipher("k1", map[string][]byte{"k1": {1, 2, 3}})
+	assert.ErrorIs(t, err, aes.KeySizeError(3))
+}
+
+func TestMapper_EncodeEncrypted(t *testing.T) {
+	cipher, err := mapper.NewAESCipher("k1", map[string][]byte{"k1": key1})
+	assert.IsNil(t, err)
+	m := mapper.New(mapper.WithCipher(cipher))
+
+	secret := "secret"
+	patient := testtypes.Patient{
+		Name:   "name1",
+		SSN:    "123-45-6789",
+		Notes:  []byte("notes"),
+		Secret: &secret,
+	}
+
+	record, err := m.Encode(&patient)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["name"], any("name1"))
+	for binName, plaintext := range map[string]string{
+		"ssn": "123-45-6789", "notes": "notes", "secret": "secret",
+	} {
+		ciphertext, ok := record.Bins[binName].([]byte)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, bytes.Contains(ciphertext, []byte(plaintext)), false)
+	}
+
+	var decoded testtypes.Patient
+	err = m.Decode(&testtypes.Record{Bins: testtypes.BinMap(record.Bins)}, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, patient)
+
+	// the default mapper has no cipher configured
+	_, err = mapper.Encode(&patient)
+	assert.ErrorIs(t, err, mapper.ErrCipherNotConfigured)
+
+	err = mapper.Decode(record, &decoded)
+	assert.ErrorIs(t, err, mapper.ErrCipherNotConfigured)
+
+	// plaintext values are not decrypted
+	err = m.Decode(&testtypes.Record{Bins: testtypes.BinMap{"ssn": "123-45-6789"}}, &decoded)
+	assert.ErrorIs(t, err, mapper.ErrInvalidCiphertext)
+}
+
+func TestMapper_EncryptedOperations(t *testing.T) {
+	cipher, err := mapper.NewAESCipher("k1", map[string][]byte{"k1": key1})
+	assert.IsNil(t, err)
+	m := mapper.New(mapper.WithCipher(cipher))
+
+	patient := testtypes.Patient{Name: "name1", SSN: "123-45-6789"}
+
+	record, _, err := m.EncodeForUpdate(&patient)
+	assert.IsNil(t, err)
+	_, ok := record.Bins["ssn"].([]byte)
+	assert.Equal(t, ok, true)
+
+	// the encrypted bins are compared by their plaintext values
+	updated := patient
+	updated.Name = "name2"
+	diff, err := m.Diff(&patient, &updated)
+	assert.IsNil(t, err)
+	assert.Equal(t, diff.Bins, map[string]any{"name": "name2"})
+
+	snapshot, err := m.NewSnapshot(&patient)
+	assert.IsNil(t, err)
+	updated.SSN = "987-65-4321"
+	diff, err = snapshot.Diff(&updated)
+	assert.IsNil(t, err)
+	assert.Equal(t, len(diff.Bins), 2)
+	_, ok = diff.Bins["ssn"].([]byte)
+	assert.Equal(t, ok, true)
+
+	records, errs, err := m.EncodeAll([]testtypes.Patient{patient, updated})
+	assert.IsNil(t, err)
+	assert.Equal(t, errs, []error{nil, nil})
+
+	batch := []*testtypes.BatchRecord{
+		{Record: &testtypes.Record{Bins: testtypes.BinMap(records[0].Bins)}},
+		{Record: &testtypes.Record{Bins: testtypes.BinMap(records[1].Bins)}},
+	}
+	var patients []testtypes.Patient
+	errs, err = m.DecodeBatch(batch, &patients)
+	assert.IsNil(t, err)
+	assert.Equal(t, errs, []error{nil, nil})
+	assert.Equal(t, patients, []testtypes.Patient{patient, updated})
+
+	in := make(chan any, 1)
+	in <- batch[1].Record
+	close(in)
+	values, streamErrs := mapper.DecodeStream[testtypes.Patient](context.Background(), in,
+		mapper.WithMapper(m))
+	assert.Equal(t, <-values, updated)
+	assert.IsNil(t, <-streamErrs)
+
+	_, _, err = mapper.EncodeForUpdate(&patient)
+	assert.ErrorIs(t, err, mapper.ErrCipherNotConfigured)
+}
@@ -0,0 +1,202 @@
+package mapper
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// CodecGzip is the ID of the built-in gzip Codec.
+	CodecGzip byte = 1
+	// CodecFlate is the ID of the built-in flate Codec.
+	CodecFlate byte = 2
+
+	// DefaultCompressionThreshold is the default minimum size in bytes of the
+	// values to compress.
+	DefaultCompressionThreshold = 1024
+)
+
+// compressedMagic is the prefix of the compressed value blobs, followed by the
+// codec ID and the compressed data.
+var compressedMagic = []byte{0xae, 'C', 'Z'}
+
+const (
+	// codecStored is the reserved codec ID of the uncompressed value blobs.
+	codecStored byte = 0
+	// maxReservedCodecID is the maximum codec ID reserved for the built-in codecs.
+	maxReservedCodecID byte = 15
+)
+
+// Codec compresses and decompresses the values of the fields tagged with the
+// `compress` option. The codec ID is stored with the compressed data to select the
+// codec on decompression. The IDs up to 15 are reserved for the built-in codecs.
+type Codec interface {
+	// ID returns the codec ID.
+	ID() byte
+	// Compress compresses the data.
+	Compress(data []byte) ([]byte, error)
+	// Decompress decompresses the data.
+	Decompress(data []byte) ([]byte, error)
+}
+
+// gzipCodec is the built-in gzip Codec.
+type gzipCodec struct {
+	level int
+}
+
+// NewGzipCodec returns a new gzip Codec with the given compression level,
+// as defined in the compress/gzip package.
+func NewGzipCodec(level int) Codec {
+	return &gzipCodec{level: level}
+}
+
+// ID returns the gzip codec ID.
+func (c *gzipCodec) ID() byte {
+	return CodecGzip
+}
+
+// Compress compresses the data using gzip.
+func (c *gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses the gzip data.
+func (c *gzipCodec) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// flateCodec is the built-in flate Codec.
+type flateCodec struct {
+	level int
+}
+
+// NewFlateCodec returns a new flate Codec with the given compression level,
+// as defined in the compress/flate package.
+func NewFlateCodec(level int) Codec {
+	return &flateCodec{level: level}
+}
+
+// ID returns the flate codec ID.
+func (c *flateCodec) ID() byte {
+	return CodecFlate
+}
+
+// Compress compresses the data using flate.
+func (c *flateCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses the flate data.
+func (c *flateCodec) Decompress(data []byte) ([]byte, error) {
+	reader := flate.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// checkCodec returns an error if the codec is nil, or if a custom codec uses an ID
+// reserved for the built-in codecs, which would make the stored values unreadable.
+func checkCodec(codec Codec) error {
+	switch codec.(type) {
+	case nil:
+		return errors.New("nil compression codec")
+	case *gzipCodec, *flateCodec:
+		return nil
+	}
+	if id := codec.ID(); id <= maxReservedCodecID {
+		return fmt.Errorf("compression codec ID %d is reserved for the built-in codecs", id)
+	}
+	return nil
+}
+
+// compress compresses the data using the Mapper codec, if it is not smaller than
+// the compression threshold. Returns false if the data is returned as is.
+// Empty data is never compressed. Data below the threshold that starts with the
+// blob magic is stored in an uncompressed blob, so that it is not mistaken for
+// a compressed one on decode.
+func (m *Mapper) compress(data []byte) ([]byte, bool, error) {
+	if len(data) == 0 || len(data) < m.compressionThreshold {
+		if bytes.HasPrefix(data, compressedMagic) {
+			return compressedBlob(codecStored, data), true, nil
+		}
+		return data, false, nil
+	}
+
+	compressed, err := m.codec.Compress(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("compress: %w", err)
+	}
+
+	return compressedBlob(m.codec.ID(), compressed), true, nil
+}
+
+// compressedBlob returns the value blob with the given codec ID and data.
+func compressedBlob(codecID byte, data []byte) []byte {
+	blob := make([]byte, 0, len(compressedMagic)+1+len(data))
+	blob = append(blob, compressedMagic...)
+	blob = append(blob, codecID)
+	return append(blob, data...)
+}
+
+// decompress decompresses the value blob of a field tagged with `compress`. The blob
+// header is validated rather than guessed: data without the blob magic was written
+// below the compression threshold, or before enabling the compression, and is
+// returned as is, while a blob with a truncated header or an unknown codec ID is
+// an error.
+func (m *Mapper) decompress(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, compressedMagic) {
+		return data, nil
+	}
+
+	headerSize := len(compressedMagic) + 1
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("%w: truncated blob header", ErrInvalidCompressed)
+	}
+
+	codecID := data[len(compressedMagic)]
+	if codecID == codecStored {
+		return data[headerSize:], nil
+	}
+
+	codec, ok := m.codecs[codecID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, codecID)
+	}
+
+	decompressed, err := codec.Decompress(data[headerSize:])
+	if err != nil {
+		return nil, fmt.Errorf("decompress: %w", err)
+	}
+
+	return decompressed, nil
+}
@@ -0,0 +1,164 @@
+package mapper_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"strings"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+type reverseCodec struct{}
+
+func (reverseCodec) ID() byte { return 100 }
+
+func (reverseCodec) Compress(data []byte) ([]byte, error) {
+	result := make([]byte, len(data))
+	for i := range data {
+		result[len(data)-1-i] = data[i]
+	}
+	return result, nil
+}
+
+func (c reverseCodec) Decompress(data []byte) ([]byte, error) {
+	return c.Compress(data)
+}
+
+func TestMapper_EncodeCompressed(t *testing.T) {
+	payload := []byte(strings.Repeat("{\"key\": \"value\"}", 100))
+	document := testtypes.Document{Title: "title1", Payload: payload}
+
+	record, err := mapper.Encode(&document)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["title"], any("title1")) // below the threshold
+	compressed := record.Bins["payload"].([]byte)
+	assert.Equal(t, len(compressed) < len(payload), true)
+	assert.Equal(t, compressed[3], mapper.CodecGzip)
+
+	var decoded testtypes.Document
+	err = mapper.Decode(record, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, document)
+
+	// uncompressed legacy values
+	err = mapper.Decode(&testtypes.Record{Bins: testtypes.BinMap{
+		"title": "title2", "payload": []byte("payload2"),
+	}}, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, testtypes.Document{Title: "title2", Payload: []byte("payload2")})
+}
+
+func TestMapper_EncodeCompressedMagic(t *testing.T) {
+	magic := []byte{0xae, 'C', 'Z', mapper.CodecGzip, 'x'}
+
+	// raw values starting with the blob magic are kept apart from the compressed ones
+	document := testtypes.Document{Payload: magic}
+	record, err := mapper.Encode(&document)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["payload"], any(append([]byte{0xae, 'C', 'Z', 0}, magic...)))
+
+	var decoded testtypes.Document
+	err = mapper.Decode(record, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, document)
+
+	// the fields without the compress option are never decompressed
+	type rawDocument struct {
+		Payload []byte `aero:"payload"`
+	}
+	var raw rawDocument
+	err = mapper.Decode(&testtypes.Record{Bins: testtypes.BinMap{"payload": magic}}, &raw)
+	assert.IsNil(t, err)
+	assert.Equal(t, raw, rawDocument{Payload: magic})
+
+	err = mapper.Decode(&testtypes.Record{Bins: testtypes.BinMap{
+		"payload": []byte{0xae, 'C', 'Z'},
+	}}, &decoded)
+	assert.ErrorIs(t, err, mapper.ErrInvalidCompressed)
+}
+
+func TestMapper_EncodeCompressedCodec(t *testing.T) {
+	document := testtypes.Document{Title: strings.Repeat("title", 10)}
+
+	m := mapper.New(mapper.WithCodec(mapper.NewFlateCodec(flate.BestCompression)),
+		mapper.WithCompressionThreshold(16))
+	record, err := m.Encode(&document)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["title"].([]byte)[3], mapper.CodecFlate)
+
+	// the built-in codecs are always available for decompression
+	var decoded testtypes.Document
+	err = mapper.Decode(record, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, document)
+
+	m = mapper.New(mapper.WithCodec(reverseCodec{}), mapper.WithCompressionThreshold(0))
+	record, err = m.Encode(&document)
+	assert.IsNil(t, err)
+
+	err = m.Decode(record, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, document)
+
+	err = mapper.Decode(record, &decoded)
+	assert.ErrorIs(t, err, mapper.ErrUnknownCodec)
+}
+
+type idCodec struct {
+	reverseCodec
+	id byte
+}
+
+func (c idCodec) ID() byte { return c.id }
+
+func TestMapper_WithCodecInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec mapper.Codec
+	}{
+		{name: "nil", codec: nil},
+		{name: "stored", codec: idCodec{id: 0}},
+		{name: "gzip", codec: idCodec{id: mapper.CodecGzip}},
+		{name: "flate", codec: idCodec{id: mapper.CodecFlate}},
+		{name: "reserved", codec: idCodec{id: 15}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				message, _ := recover().(string)
+				assert.Equal(t, strings.Contains(message, "codec"), true)
+			}()
+			mapper.New(mapper.WithCodec(test.codec))
+			t.Fatal("expected a panic")
+		})
+	}
+
+	// the built-in codecs can be configured
+	mapper.New(mapper.WithCodec(mapper.NewGzipCodec(1)), mapper.WithCodec(idCodec{id: 16}))
+}
+
+func TestMapper_EncodeCompressedEncrypted(t *testing.T) {
+	cipher, err := mapper.NewAESCipher("k1", map[string][]byte{"k1": key1})
+	assert.IsNil(t, err)
+	m := mapper.New(mapper.WithCipher(cipher), mapper.WithCompressionThreshold(0))
+
+	type secretDocument struct {
+		Secret string `aero:"secret,compress,encrypt"`
+	}
+
+	document := secretDocument{Secret: strings.Repeat("secret", 100)}
+	record, err := m.Encode(&document)
+	assert.IsNil(t, err)
+	ciphertext := record.Bins["secret"].([]byte)
+	assert.Equal(t, len(ciphertext) < len(document.Secret), true)
+	assert.Equal(t, bytes.Contains(ciphertext, []byte("secret")), false)
+
+	var decoded secretDocument
+	err = m.Decode(record, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, document)
+}
@@ -0,0 +1,149 @@
+package mapper
+
+import (
+	"reflect"
+
+	"github.com/reugn/aerospike-mapper-go/internal/deepcopy"
+)
+
+// Snapshot holds the encoded bins of a value captured at a point in time, typically
+// at decode time, to track the changes made to the value since then.
+type Snapshot struct {
+	mapper *Mapper
+	bins   map[string]any
+}
+
+// NewSnapshot captures the current state of v, which must be a struct or struct pointer.
+func NewSnapshot(v any) (*Snapshot, error) {
+	return defaultMapper.NewSnapshot(v)
+}
+
+// NewSnapshot captures the current state of v using the Mapper configuration.
+// The Diff of the returned Snapshot encodes the values using the same Mapper.
+func (m *Mapper) NewSnapshot(v any) (*Snapshot, error) {
+	_, bins, err := m.encodeForDiff(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{
+		mapper: m,
+		bins:   deepcopy.Copy(bins).(map[string]any),
+	}, nil
+}
+
+// DecodeWithSnapshot decodes the record into v as Decode does, and returns
+// the Snapshot of the decoded value.
+func DecodeWithSnapshot(record, v any) (*Snapshot, error) {
+	return defaultMapper.DecodeWithSnapshot(record, v)
+}
+
+// DecodeWithSnapshot decodes the record into v using the Mapper configuration, and
+// returns the Snapshot of the decoded value.
+func (m *Mapper) DecodeWithSnapshot(record, v any) (*Snapshot, error) {
+	if err := m.Decode(record, v); err != nil {
+		return nil, err
+	}
+	return m.NewSnapshot(v)
+}
+
+// Diff encodes v and returns a record with the key and metadata of v, containing only
+// the bins changed since the snapshot was taken. The bins that are no longer encoded
+// are set to nil, which deletes them on write. The lifecycle hooks and the validation
+// rules are not applied, use Validate to check v before writing the record.
+func (s *Snapshot) Diff(v any) (*Record, error) {
+	record, bins, err := s.mapper.encodeForDiff(v)
+	if err != nil {
+		return nil, err
+	}
+
+	diffBins(s.bins, bins, record)
+	return record, nil
+}
+
+// Diff encodes the old and new values and returns a record with the key and metadata
+// of the new value, containing only the bins that changed. The bins encoded for the old
+// value but not for the new value are set to nil, which deletes them on write.
+// The values must be structs or struct pointers, typically of the same type. The
+// lifecycle hooks and the validation rules are not applied to the values.
+func Diff(oldValue, newValue any) (*Record, error) {
+	return defaultMapper.Diff(oldValue, newValue)
+}
+
+// Diff encodes the old and new values using the Mapper configuration, and returns
+// a record containing only the bins that changed. See the package level Diff function
+// for details.
+func (m *Mapper) Diff(oldValue, newValue any) (*Record, error) {
+	_, oldBins, err := m.encodeForDiff(oldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	record, bins, err := m.encodeForDiff(newValue)
+	if err != nil {
+		return nil, err
+	}
+
+	diffBins(oldBins, bins, record)
+	return record, nil
+}
+
+// encodeForDiff encodes v and returns the record along with the bins to compare.
+// The compared bins hold the values of the transformed bins before the transformation,
+// since the encrypted values differ on every encoding. The lifecycle hooks and the
+// validation rules are skipped, so that v is not modified, and the stored values that
+// break the rules can still be tracked.
+func (m *Mapper) encodeForDiff(v any) (*Record, map[string]any, error) {
+	record := &Record{
+		Bins: make(map[string]any),
+	}
+	plain := make(map[string]any)
+	if err := m.encodeBins(v, record, plain); err != nil {
+		return nil, nil, err
+	}
+	if err := m.checkSize(record); err != nil {
+		return nil, nil, err
+	}
+
+	bins := make(map[string]any, len(record.Bins))
+	for binName, binValue := range record.Bins {
+		bins[binName] = binValue
+	}
+	for binName, binValue := range plain {
+		bins[binName] = binValue
+	}
+
+	return record, bins, nil
+}
+
+// diffBins removes the bins of the record whose compared values are equal to the old
+// bins, and sets the old bins that are missing in the record to nil.
+func diffBins(oldBins, bins map[string]any, record *Record) {
+	for binName, oldValue := range oldBins {
+		if _, ok := record.Bins[binName]; !ok && !isNilBin(oldValue) {
+			record.Bins[binName] = nil
+		}
+	}
+
+	for binName := range record.Bins {
+		oldValue, ok := oldBins[binName]
+		if ok && reflect.DeepEqual(oldValue, bins[binName]) {
+			delete(record.Bins, binName)
+		}
+	}
+}
+
+// isNilBin reports whether the bin value is nil, or a typed nil pointer, slice or map.
+func isNilBin(binValue any) bool {
+	if binValue == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(binValue)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,119 @@
+package mapper_test
+
+import (
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_Diff(t *testing.T) {
+	oldItem := &testtypes.Item{
+		Key:         mapper.Key{Namespace: "ns1", SetName: "set1"},
+		Length:      10,
+		Description: "description1",
+		IntList:     []int{1, 2},
+		Dict:        map[string]int{"a": 1},
+	}
+	newItem := &testtypes.Item{
+		Key:      mapper.Key{Namespace: "ns1", SetName: "set1"},
+		Metadata: mapper.Metadata{Generation: 2},
+		Item1:    testtypes.Item1{Title: "title1"},
+		Length:   10,
+		IntList:  []int{1, 2, 3},
+		Dict:     map[string]int{"a": 1},
+	}
+
+	record, err := mapper.Diff(oldItem, newItem)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Namespace, "ns1")
+	assert.Equal(t, record.Generation, 2)
+	assert.Equal(t, record.Bins, map[string]any{
+		"title":       "title1",
+		"description": nil,
+		"list":        []int{1, 2, 3},
+	})
+}
+
+func TestMapper_Snapshot(t *testing.T) {
+	record1, err := newTestRecord()
+	assert.IsNil(t, err)
+
+	var item testtypes.Item
+	snapshot, err := mapper.DecodeWithSnapshot(record1, &item)
+	assert.IsNil(t, err)
+
+	record, err := snapshot.Diff(&item)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.UserKey, any("key1"))
+	assert.Equal(t, len(record.Bins), 0)
+
+	// modify in place
+	item.IntList[0] = 10
+	item.Dict["d"] = 4
+	item.Length = 0
+
+	record, err = snapshot.Diff(&item)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins, map[string]any{
+		"list":   []int{10, 2, 3},
+		"dict":   map[string]int{"a": 1, "b": 2, "c": 3, "d": 4},
+		"length": 0,
+	})
+
+	// clear the omitempty field
+	item.Dict = nil
+	record, err = snapshot.Diff(&item)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["dict"], nil)
+	_, ok := record.Bins["dict"]
+	assert.Equal(t, ok, true)
+}
+
+func TestMapper_SnapshotHooks(t *testing.T) {
+	record := &testtypes.Record{
+		Bins: testtypes.BinMap{"email": "Alice@Example.com", "city": "city1"},
+	}
+
+	// the snapshot does not call the hooks on the decoded value
+	var account testtypes.Account
+	snapshot, err := mapper.DecodeWithSnapshot(record, &account)
+	assert.IsNil(t, err)
+	assert.Equal(t, account.Email, "Alice@Example.com")
+	assert.Equal(t, account.Normalized, false)
+	assert.Equal(t, account.Domain, "Example.com")
+
+	diff, err := snapshot.Diff(&account)
+	assert.IsNil(t, err)
+	assert.Equal(t, len(diff.Bins), 0)
+
+	// the BeforeAerospikeEncode failure does not fail the snapshot
+	var empty testtypes.Account
+	_, err = mapper.DecodeWithSnapshot(&testtypes.Record{Bins: testtypes.BinMap{}}, &empty)
+	assert.IsNil(t, err)
+
+	// the stored values breaking the validation rules can be tracked
+	var person testtypes.Person
+	snapshot, err = mapper.DecodeWithSnapshot(&testtypes.Record{
+		Bins: testtypes.BinMap{"name": "name1", "age": 200, "role": "admin"},
+	}, &person)
+	assert.IsNil(t, err)
+	assert.Equal(t, person.Age, 200)
+
+	person.Name = "name2"
+	diff, err = snapshot.Diff(&person)
+	assert.IsNil(t, err)
+	assert.Equal(t, diff.Bins, map[string]any{"name": "name2"})
+}
+
+func TestMapper_DiffHooks(t *testing.T) {
+	oldAccount := &testtypes.Account{Email: "Alice@Example.com"}
+	newAccount := &testtypes.Account{Email: "Bob@Example.com"}
+
+	record, err := mapper.Diff(oldAccount, newAccount)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins, map[string]any{"email": "Bob@Example.com"})
+	assert.Equal(t, *oldAccount, testtypes.Account{Email: "Alice@Example.com"})
+	assert.Equal(t, *newAccount, testtypes.Account{Email: "Bob@Example.com"})
+}
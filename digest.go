@@ -0,0 +1,61 @@
+package mapper
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/reugn/aerospike-mapper-go/internal/ripemd160"
+)
+
+// particle types of the user key values
+const (
+	keyParticleInteger = 1
+	keyParticleString  = 3
+	keyParticleBlob    = 4
+)
+
+// ComputeDigest computes the Aerospike record digest for the given set name and
+// user key, as the RIPEMD-160 hash of the set name, the key particle type and the
+// key value bytes. Supported user key types are integers, strings and byte slices.
+func ComputeDigest(setName string, userKey any) ([20]byte, error) {
+	var particleType byte
+	var keyBytes []byte
+
+	keyValue := reflect.ValueOf(userKey)
+	switch keyValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		particleType = keyParticleInteger
+		keyBytes = make([]byte, 8)
+		binary.BigEndian.PutUint64(keyBytes, uint64(keyValue.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if keyValue.Uint() > math.MaxInt64 {
+			return [20]byte{}, fmt.Errorf("%w: value '%d' overflows int64",
+				ErrInvalidUserKey, keyValue.Uint())
+		}
+		particleType = keyParticleInteger
+		keyBytes = make([]byte, 8)
+		binary.BigEndian.PutUint64(keyBytes, keyValue.Uint())
+	case reflect.String:
+		particleType = keyParticleString
+		keyBytes = []byte(keyValue.String())
+	case reflect.Slice:
+		if keyValue.Type().Elem().Kind() != reflect.Uint8 {
+			return [20]byte{}, fmt.Errorf("%w: %s", ErrInvalidUserKey, keyValue.Type())
+		}
+		particleType = keyParticleBlob
+		keyBytes = keyValue.Bytes()
+	case reflect.Invalid:
+		return [20]byte{}, fmt.Errorf("%w: nil", ErrInvalidUserKey)
+	default:
+		return [20]byte{}, fmt.Errorf("%w: %s", ErrInvalidUserKey, keyValue.Type())
+	}
+
+	data := make([]byte, 0, len(setName)+1+len(keyBytes))
+	data = append(data, setName...)
+	data = append(data, particleType)
+	data = append(data, keyBytes...)
+
+	return ripemd160.Sum(data), nil
+}
@@ -0,0 +1,56 @@
+package mapper_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+)
+
+func TestMapper_ComputeDigest(t *testing.T) {
+	digest1, err := mapper.ComputeDigest("set1", 1)
+	assert.IsNil(t, err)
+
+	// integer keys of different types produce the same digest
+	digest2, err := mapper.ComputeDigest("set1", uint8(1))
+	assert.IsNil(t, err)
+	assert.Equal(t, digest1, digest2)
+
+	digest3, err := mapper.ComputeDigest("set1", "1")
+	assert.IsNil(t, err)
+	assert.Equal(t, digest1 != digest3, true)
+
+	digest4, err := mapper.ComputeDigest("set2", 1)
+	assert.IsNil(t, err)
+	assert.Equal(t, digest1 != digest4, true)
+
+	_, err = mapper.ComputeDigest("set1", []byte{1})
+	assert.IsNil(t, err)
+
+	for _, userKey := range []any{nil, 1.5, []int{1}, uint64(1 << 63)} {
+		_, err = mapper.ComputeDigest("set1", userKey)
+		assert.ErrorIs(t, err, mapper.ErrInvalidUserKey)
+	}
+}
+
+func TestMapper_ComputeDigestGolden(t *testing.T) {
+	// the digests of as.NewKey("namespace", "set", key).Digest() of the Aerospike client
+	tests := []struct {
+		name    string
+		userKey any
+		digest  string
+	}{
+		{"int", int64(1), "82d7213b469812947c109a6d341e3b5b1dedec1f"},
+		{"string", "", "2819b1ff6e346a43b4f5f6b77a88bc3eaac22a83"},
+		{"blob", []byte{}, "327e2877b8815c7aeede0d5a8620d4ef8df4a4b4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			digest, err := mapper.ComputeDigest("set", test.userKey)
+			assert.IsNil(t, err)
+			assert.Equal(t, hex.EncodeToString(digest[:]), test.digest)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Encoder encodes values into records in bulk, using a configurable number of
+// concurrent workers. The records are allocated from a pool, and can be returned
+// to it using Release once written, to reduce the allocations of large backfills.
+// Encoder is safe for concurrent use.
+type Encoder struct {
+	config *pipelineConfig
+	pool   *RecordPool
+}
+
+// NewEncoder returns a new Encoder configured with the given options.
+// The results preserve the input order, unless WithUnordered is specified.
+// The values are encoded by the default Mapper, unless WithMapper is specified.
+func NewEncoder(opts ...PipelineOption) *Encoder {
+	return &Encoder{
+		config: newPipelineConfig(opts),
+		pool:   NewRecordPool(),
+	}
+}
+
+// NewEncoder returns a new Encoder which encodes the values using the Mapper
+// configuration. See the package level NewEncoder function for details.
+func (m *Mapper) NewEncoder(opts ...PipelineOption) *Encoder {
+	return NewEncoder(append(opts, WithMapper(m))...)
+}
+
+// EncodeAll encodes the elements of the values slice into records using
+// a new Encoder configured with the given options.
+// See Encoder.EncodeAll for details.
+func EncodeAll(values any, opts ...PipelineOption) ([]*Record, []error, error) {
+	return NewEncoder(opts...).EncodeAll(values)
+}
+
+// EncodeAll encodes the elements of the values slice into records using a new
+// Encoder with the Mapper configuration. See Encoder.EncodeAll for details.
+func (m *Mapper) EncodeAll(values any, opts ...PipelineOption) ([]*Record, []error, error) {
+	return m.NewEncoder(opts...).EncodeAll(values)
+}
+
+// EncodeAll encodes the elements of the values slice into records.
+// The elements must be structs or struct pointers.
+//
+// The returned records and errors slices are positional, with the same length as
+// the values slice. An element that failed to encode results in a nil record and
+// an *ItemError at its position. The non-nil error is returned only if values is
+// not a slice. The results are always in the input order.
+func (e *Encoder) EncodeAll(values any) ([]*Record, []error, error) {
+	valuesValue := reflect.ValueOf(values)
+	if valuesValue.Kind() == reflect.Ptr {
+		valuesValue = valuesValue.Elem()
+	}
+	if valuesValue.Kind() != reflect.Slice {
+		return nil, nil, fmt.Errorf("%w: values must be a slice", ErrInvalidSourceType)
+	}
+
+	n := valuesValue.Len()
+	records := make([]*Record, n)
+	errs := make([]error, n)
+
+	indexes := make(chan int)
+	go func() {
+		defer close(indexes)
+		for i := 0; i < n; i++ {
+			indexes <- i
+		}
+	}()
+
+	runPipeline(context.Background(), indexes, e.config,
+		func(i int) (*Record, error) {
+			element := valuesValue.Index(i)
+			if element.Kind() == reflect.Struct && element.CanAddr() {
+				// avoid copying the struct
+				element = element.Addr()
+			}
+			return e.encode(element.Interface())
+		},
+		func(result pipelineResult[*Record]) bool {
+			// the index of the indexes channel item matches the slice index
+			if result.err != nil {
+				errs[result.index] = &ItemError{Index: result.index, Err: result.err}
+			} else {
+				records[result.index] = result.value
+			}
+			return true
+		})
+
+	return records, errs, nil
+}
+
+// EncodeStream encodes the values read from the in channel into records.
+//
+// The encoded records are sent to the returned records channel, and the failures to
+// the returned errors channel as *ItemError, with the index of the value in the input.
+// Both channels are closed when the in channel is closed and all the values are
+// processed, or when the context is cancelled. The channels must be consumed
+// concurrently, unless the context is cancelled.
+func (e *Encoder) EncodeStream(ctx context.Context, in <-chan any) (<-chan *Record, <-chan error) {
+	records := make(chan *Record, e.config.bufferSize)
+	errs := make(chan error, e.config.bufferSize)
+
+	go func() {
+		defer close(records)
+		defer close(errs)
+
+		runPipeline(ctx, in, e.config, e.encode,
+			func(result pipelineResult[*Record]) bool {
+				if result.err != nil {
+					return send[error](ctx, errs, &ItemError{Index: result.index, Err: result.err})
+				}
+				if !send(ctx, records, result.value) {
+					e.Release(result.value)
+					return false
+				}
+				return true
+			})
+	}()
+
+	return records, errs
+}
+
+// Release returns the records to the pool of the Encoder for reuse.
+// The records must not be used after being released.
+func (e *Encoder) Release(records ...*Record) {
+	for _, record := range records {
+		e.pool.Put(record)
+	}
+}
+
+// encode encodes v into a record obtained from the pool.
+func (e *Encoder) encode(v any) (*Record, error) {
+	record := e.pool.Get()
+	if err := e.config.mapper.EncodeInto(v, record); err != nil {
+		e.pool.Put(record)
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,97 @@
+package mapper_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_EncodeAll(t *testing.T) {
+	const n = 100
+	users := make([]testtypes.User, n)
+	for i := range users {
+		users[i] = testtypes.User{Name: fmt.Sprint(i), LoginCount: i}
+	}
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers%d", workers), func(t *testing.T) {
+			records, errs, err := mapper.EncodeAll(users, mapper.WithWorkers(workers))
+			assert.IsNil(t, err)
+			assert.Equal(t, len(records), n)
+			assert.Equal(t, len(errs), n)
+			for i, record := range records {
+				assert.IsNil(t, errs[i])
+				assert.Equal(t, record.Namespace, "test")
+				assert.Equal(t, record.Bins, map[string]any{
+					"name":        fmt.Sprint(i),
+					"login_count": i,
+				})
+			}
+		})
+	}
+
+	records, errs, err := mapper.EncodeAll([]any{&users[0], 1, users[2]})
+	assert.IsNil(t, err)
+	assert.Equal(t, records[0].Bins["name"], any("0"))
+	assert.IsNil(t, records[1])
+	assert.Equal(t, records[2].Bins["name"], any("2"))
+	assert.IsNil(t, errs[0])
+	assert.ErrorIs(t, errs[1], mapper.ErrInvalidSourceType)
+	var itemErr *mapper.ItemError
+	assert.Equal(t, errors.As(errs[1], &itemErr), true)
+	assert.Equal(t, itemErr.Index, 1)
+
+	_, _, err = mapper.EncodeAll(users[0])
+	assert.ErrorIs(t, err, mapper.ErrInvalidSourceType)
+}
+
+func TestEncoder_EncodeStream(t *testing.T) {
+	const n = 50
+	encoder := mapper.NewEncoder(mapper.WithWorkers(3), mapper.WithBufferSize(5))
+
+	in := make(chan any)
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			if i == 10 {
+				in <- "invalid"
+				continue
+			}
+			in <- &testtypes.User{Name: fmt.Sprint(i)}
+		}
+	}()
+
+	records, errs := encoder.EncodeStream(context.Background(), in)
+
+	var errIndexes []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for err := range errs {
+			var itemErr *mapper.ItemError
+			if errors.As(err, &itemErr) {
+				errIndexes = append(errIndexes, itemErr.Index)
+			}
+		}
+	}()
+
+	i := 0
+	for record := range records {
+		if i == 10 {
+			i++
+		}
+		assert.Equal(t, record.Bins["name"], any(fmt.Sprint(i)))
+		// the released record is reused with its bins cleared
+		encoder.Release(record)
+		i++
+	}
+	<-done
+
+	assert.Equal(t, i, n)
+	assert.Equal(t, errIndexes, []int{10})
+}
@@ -5,4 +5,18 @@ import "errors"
 var (
 	ErrInvalidSource     = errors.New("source does not contain aerospike record")
 	ErrInvalidSourceType = errors.New("source must be a struct or a pointer to a struct")
+	ErrInvalidUserKey    = errors.New("unsupported user key type")
+	ErrInvalidTargetType = errors.New("unsupported target type")
+	ErrRecordNotFound    = errors.New("record not found")
+	ErrFieldNotMapped    = errors.New("field is not mapped to a bin")
+	ErrInvalidTag        = errors.New("invalid tag")
+
+	ErrCipherNotConfigured = errors.New("cipher is not configured for the encrypted field")
+	ErrInvalidCiphertext   = errors.New("invalid ciphertext")
+	ErrUnknownKeyID        = errors.New("unknown encryption key ID")
+	ErrUnknownCodec        = errors.New("unknown compression codec")
+	ErrInvalidCompressed   = errors.New("invalid compressed value")
+	ErrRecordTooLarge      = errors.New("record too large")
+	ErrMissingChunk        = errors.New("missing large object chunk")
+	ErrInvalidChunkSize    = errors.New("invalid chunk size")
 )
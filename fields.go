@@ -0,0 +1,65 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// fieldsCache caches the parsed field details of the struct types.
+var fieldsCache sync.Map // map[reflect.Type][]fieldInfo
+
+// fieldInfo holds the parsed mapping details of a struct field.
+type fieldInfo struct {
+	// tagged indicates that the field has the `aero` tag.
+	tagged bool
+	// tag is the parsed `aero` tag.
+	tag tag
+	// zero is the zero value of the field type.
+	zero any
+	// rules contains the compiled validation rules of the field.
+	rules []rule
+}
+
+// cachedFields returns the parsed details of the fields of the struct type t,
+// indexed by the field index. The result is cached to avoid parsing the tags
+// on every call.
+func cachedFields(t reflect.Type) ([]fieldInfo, error) {
+	if cached, ok := fieldsCache.Load(t); ok {
+		return cached.([]fieldInfo), nil
+	}
+
+	fields := make([]fieldInfo, t.NumField())
+	for i := range fields {
+		field := t.Field(i)
+		fields[i].zero = reflect.Zero(field.Type).Interface()
+
+		aeroTag := field.Tag.Get(mapperTag)
+		if aeroTag == "" {
+			continue
+		}
+
+		tag, err := parseTag(aeroTag)
+		if err != nil {
+			return nil, err
+		}
+		fields[i].tagged = true
+		fields[i].tag = tag
+
+		if tag.ordered && field.Type.Kind() != reflect.Map {
+			return nil, fmt.Errorf("field %s: %w: ordered requires a map type, got %s",
+				field.Name, ErrInvalidTag, field.Type)
+		}
+
+		for _, text := range tag.rules {
+			compiled, err := compileRule(text, field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			fields[i].rules = append(fields[i].rules, compiled)
+		}
+	}
+
+	fieldsCache.Store(t, fields)
+	return fields, nil
+}
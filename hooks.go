@@ -0,0 +1,187 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// BeforeEncoder is an optional interface to be implemented by the data model types
+// to normalize their values before encoding.
+type BeforeEncoder interface {
+	BeforeAerospikeEncode() error
+}
+
+// AfterDecoder is an optional interface to be implemented by the data model types
+// to compute derived values after decoding.
+type AfterDecoder interface {
+	AfterAerospikeDecode() error
+}
+
+// Validator is an optional interface to be implemented by the data model types
+// to validate their values before encoding. It is called after BeforeAerospikeEncode.
+type Validator interface {
+	ValidateAerospike() error
+}
+
+const (
+	hookBeforeEncode = "BeforeAerospikeEncode"
+	hookAfterDecode  = "AfterAerospikeDecode"
+	hookValidate     = "ValidateAerospike"
+)
+
+// HookError is returned when a lifecycle hook of the encoded or decoded value fails.
+type HookError struct {
+	// Hook is the name of the failed hook method.
+	Hook string
+	// Type is the type that implements the hook.
+	Type reflect.Type
+	// Err is the error returned by the hook.
+	Err error
+}
+
+// Error returns the string representation of the error.
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s of %s: %v", e.Hook, e.Type, e.Err)
+}
+
+// Unwrap returns the error returned by the hook.
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// hooksCache caches whether the struct types, or any of their nested structs,
+// implement the lifecycle hook interfaces.
+var hooksCache sync.Map // map[reflect.Type]bool
+
+// hasHooks reports whether the struct type t, or any of its nested structs,
+// implements any of the lifecycle hook interfaces.
+func hasHooks(t reflect.Type) bool {
+	if cached, ok := hooksCache.Load(t); ok {
+		return cached.(bool)
+	}
+
+	result := findHooks(t, make(map[reflect.Type]struct{}))
+	hooksCache.Store(t, result)
+	return result
+}
+
+// findHooks walks the struct type t, guarding against recursive types.
+func findHooks(t reflect.Type, visited map[reflect.Type]struct{}) bool {
+	if _, ok := visited[t]; ok {
+		return false
+	}
+	visited[t] = struct{}{}
+
+	ptrType := reflect.PointerTo(t)
+	if ptrType.Implements(reflect.TypeOf((*BeforeEncoder)(nil)).Elem()) ||
+		ptrType.Implements(reflect.TypeOf((*AfterDecoder)(nil)).Elem()) ||
+		ptrType.Implements(reflect.TypeOf((*Validator)(nil)).Elem()) {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType.String() != timeType &&
+			findHooks(fieldType, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callHooks calls the hook function for the nested structs of the struct value,
+// and then for the value itself. Embedded structs are walked for their nested
+// structs, but the hook is not called on them, since their methods are promoted
+// to the containing struct.
+func callHooks(value reflect.Value, self bool, hook func(any) error) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := fieldValueDeref(value, i)
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type().String() != timeType {
+			if err := callHooks(fieldValue, !field.Anonymous, hook); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !self {
+		return nil
+	}
+	if value.CanAddr() {
+		return hook(value.Addr().Interface())
+	}
+	return hook(value.Interface())
+}
+
+// beforeEncode calls the BeforeAerospikeEncode and then the ValidateAerospike hooks
+// of v and its nested structs, and returns the value to encode.
+// A non-pointer v is copied, so that the changes made by the hooks are encoded.
+func beforeEncode(v any) (any, error) {
+	value, err := structValue(v)
+	if err != nil || !hasHooks(value.Type()) {
+		return v, err
+	}
+
+	if !value.CanAddr() {
+		ptrValue := reflect.New(value.Type())
+		ptrValue.Elem().Set(value)
+		value = ptrValue.Elem()
+		v = ptrValue.Interface()
+	}
+
+	err = callHooks(value, true, func(target any) error {
+		if h, ok := target.(BeforeEncoder); ok {
+			if err := h.BeforeAerospikeEncode(); err != nil {
+				return &HookError{Hook: hookBeforeEncode, Type: reflect.TypeOf(target), Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = callHooks(value, true, func(target any) error {
+		if h, ok := target.(Validator); ok {
+			if err := h.ValidateAerospike(); err != nil {
+				return &HookError{Hook: hookValidate, Type: reflect.TypeOf(target), Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// afterDecode calls the AfterAerospikeDecode hooks of v and its nested structs.
+func afterDecode(v any) error {
+	value, err := structValue(v)
+	if err != nil || !hasHooks(value.Type()) {
+		return err
+	}
+
+	return callHooks(value, true, func(target any) error {
+		if h, ok := target.(AfterDecoder); ok {
+			if err := h.AfterAerospikeDecode(); err != nil {
+				return &HookError{Hook: hookAfterDecode, Type: reflect.TypeOf(target), Err: err}
+			}
+		}
+		return nil
+	})
+}
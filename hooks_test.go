@@ -0,0 +1,60 @@
+package mapper_test
+
+import (
+	"errors"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_EncodeHooks(t *testing.T) {
+	account := testtypes.Account{Email: "User@Example.com", Address: testtypes.Address{City: "city1"}}
+	record, err := mapper.Encode(&account)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins, map[string]any{
+		"email": "user@example.com", "address_city": "city1", "city": "", "zip": 0,
+	})
+	assert.Equal(t, account.Normalized, true)
+
+	// the value is copied, so the hooks do not modify the original
+	value := testtypes.Account{Email: "User@Example.com"}
+	record, err = mapper.Encode(value)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["email"], any("user@example.com"))
+	assert.Equal(t, value.Normalized, false)
+}
+
+func TestMapper_EncodeHooksError(t *testing.T) {
+	var hookErr *mapper.HookError
+
+	_, err := mapper.Encode(&testtypes.Account{})
+	assert.Equal(t, errors.As(err, &hookErr), true)
+	assert.Equal(t, hookErr.Hook, "BeforeAerospikeEncode")
+	assert.Equal(t, err.Error(),
+		"BeforeAerospikeEncode of *testtypes.Account: empty email")
+
+	_, err = mapper.Encode(&testtypes.Account{
+		Email:   "user@example.com",
+		Address: testtypes.Address{City: "invalid"},
+	})
+	assert.Equal(t, errors.As(err, &hookErr), true)
+	assert.Equal(t, hookErr.Hook, "ValidateAerospike")
+	assert.Equal(t, hookErr.Err.Error(), "invalid city")
+}
+
+func TestMapper_DecodeHooks(t *testing.T) {
+	record := &testtypes.Record{
+		Bins: testtypes.BinMap{"email": "user@example.com", "address_city": "city1"},
+	}
+
+	var account testtypes.Account
+	err := mapper.Decode(record, &account)
+	assert.IsNil(t, err)
+	assert.Equal(t, account, testtypes.Account{
+		Email:   "user@example.com",
+		Address: testtypes.Address{City: "city1", Verified: true},
+		Domain:  "example.com",
+	})
+}
@@ -0,0 +1,69 @@
+// Package deepcopy implements deep copying of arbitrary values.
+package deepcopy
+
+import "reflect"
+
+// Copy returns a deep copy of v. Slices, arrays, maps, pointers and the exported
+// fields of structs are copied recursively; other values are returned as is.
+func Copy(v any) any {
+	if v == nil {
+		return nil
+	}
+	return Value(reflect.ValueOf(v)).Interface()
+}
+
+// Value returns a deep copy of the given reflect.Value.
+func Value(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(Value(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(Value(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(Value(iter.Key()), Value(iter.Value()))
+		}
+		return c
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(Value(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(Value(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(Value(v.Field(i)))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
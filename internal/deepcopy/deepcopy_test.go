@@ -0,0 +1,47 @@
+package deepcopy
+
+import (
+	"reflect"
+	"testing"
+)
+
+type item struct {
+	List []int
+	Ptr  *string
+	Map  map[string]any
+	name string
+}
+
+func TestCopy(t *testing.T) {
+	str := "str"
+	original := map[string]any{
+		"list": []any{1, []int{2, 3}},
+		"item": item{
+			List: []int{1},
+			Ptr:  &str,
+			Map:  map[string]any{"a": []byte{1}},
+			name: "name",
+		},
+		"array": [2][]int{{1}, {2}},
+		"nil":   nil,
+	}
+
+	copied := Copy(original).(map[string]any)
+	if !reflect.DeepEqual(original, copied) {
+		t.Fatalf("%v != %v", original, copied)
+	}
+
+	copied["list"].([]any)[1].([]int)[0] = 10
+	copiedItem := copied["item"].(item)
+	copiedItem.List[0] = 10
+	*copiedItem.Ptr = "changed"
+	copiedItem.Map["a"].([]byte)[0] = 10
+	copied["array"].([2][]int)[0][0] = 10
+
+	if !reflect.DeepEqual(original["list"], []any{1, []int{2, 3}}) ||
+		original["item"].(item).List[0] != 1 || str != "str" ||
+		original["item"].(item).Map["a"].([]byte)[0] != 1 ||
+		original["array"].([2][]int)[0][0] != 1 {
+		t.Fatalf("original value modified: %v", original)
+	}
+}
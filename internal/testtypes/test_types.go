@@ -1,6 +1,12 @@
 package testtypes
 
-import mapper "github.com/reugn/aerospike-mapper-go"
+import (
+	"errors"
+	"strings"
+	"time"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+)
 
 type Item1 struct {
 	Title string `aero:"title"`
@@ -25,3 +31,172 @@ type Item struct {
 	IntList     []int          `aero:"list"`
 	Dict        map[string]int `aero:"dict,omitempty"`
 }
+
+type ItemMeta struct {
+	mapper.Metadata
+	LastUpdate time.Time `aero:"meta,last_update"`
+	VoidTime   time.Time `aero:"meta,void_time"`
+	Size       int       `aero:"meta,size"`
+	Title      string    `aero:"title"`
+}
+
+type User struct {
+	mapper.Key
+	mapper.KeyValue
+	mapper.Metadata
+	Name       string `aero:"name"`
+	LoginCount int    `aero:"login_count"`
+}
+
+func (User) AerospikeNamespace() string {
+	return "test"
+}
+
+func (*User) AerospikeSet() string {
+	return "users"
+}
+
+type Nested struct {
+	City string `aero:"city"`
+	Zip  int    `aero:"zip"`
+}
+
+type AllKinds struct {
+	mapper.Key
+	mapper.KeyValue
+	mapper.Metadata
+	Nested
+	String  string            `aero:"string"`
+	Int     int               `aero:"int"`
+	Int8    int8              `aero:"int8"`
+	Int16   int16             `aero:"int16"`
+	Int32   int32             `aero:"int32"`
+	Int64   int64             `aero:"int64"`
+	Uint    uint              `aero:"uint"`
+	Uint8   uint8             `aero:"uint8"`
+	Uint16  uint16            `aero:"uint16"`
+	Uint32  uint32            `aero:"uint32"`
+	Uint64  uint64            `aero:"uint64"`
+	Float32 float32           `aero:"float32"`
+	Float64 float64           `aero:"float64"`
+	Bool    bool              `aero:"bool"`
+	Bytes   []byte            `aero:"bytes"`
+	Strings []string          `aero:"strings"`
+	Map     map[string]int    `aero:"map"`
+	Nested2 map[string][]bool `aero:"nested2"`
+	Ptr     *int              `aero:"ptr"`
+	Any     any               `aero:"any"`
+	Time    time.Time         `aero:"time"`
+}
+
+type Profile struct {
+	Name     string  `aero:"name"`
+	Nickname *string `aero:"nickname,nullable"`
+	Bio      string  `aero:"bio,nullable"`
+	Extra    any     `aero:"extra"`
+}
+
+type Address struct {
+	City     string `aero:"address_city"`
+	Verified bool
+}
+
+func (a *Address) AfterAerospikeDecode() error {
+	a.Verified = a.City != ""
+	return nil
+}
+
+func (a *Address) ValidateAerospike() error {
+	if a.City == "invalid" {
+		return errors.New("invalid city")
+	}
+	return nil
+}
+
+type Account struct {
+	Nested
+	Email      string `aero:"email"`
+	Address    Address
+	Normalized bool
+	Domain     string
+}
+
+func (a *Account) BeforeAerospikeEncode() error {
+	if a.Email == "" {
+		return errors.New("empty email")
+	}
+	a.Email = strings.ToLower(a.Email)
+	a.Normalized = true
+	return nil
+}
+
+func (a *Account) AfterAerospikeDecode() error {
+	a.Domain = a.Email[strings.IndexByte(a.Email, '@')+1:]
+	return nil
+}
+
+type Location struct {
+	Zip string `aero:"zip,len=5"`
+}
+
+type Person struct {
+	Name     string   `aero:"name,len>=1,len<=8"`
+	Age      int      `aero:"age,min=0,max=150"`
+	Role     string   `aero:"role,oneof=admin|user"`
+	Code     string   `aero:"code,regex=^[A-Z]{3}$,omitempty"`
+	Level    *uint8   `aero:"level,min=1"`
+	Tags     []string `aero:"tags,len<=2"`
+	Location Location
+}
+
+type Patient struct {
+	Name   string  `aero:"name"`
+	SSN    string  `aero:"ssn,encrypt"`
+	Notes  []byte  `aero:"notes,encrypt"`
+	Secret *string `aero:"secret,encrypt"`
+}
+
+type Document struct {
+	Title   string `aero:"title,compress"`
+	Payload []byte `aero:"payload,compress"`
+}
+
+type State struct {
+	Step   int               `json:"step"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type Workflow struct {
+	ID      string  `aero:"id"`
+	State   State   `aero:"state,json"`
+	History []State `aero:"history,gob"`
+	Current *State  `aero:"current,gob,compress"`
+}
+
+type Timeline struct {
+	mapper.Key
+	mapper.KeyValue
+	Title  string   `aero:"title"`
+	Events []string `aero:"events,chunked"`
+	Blob   []byte   `aero:"blob,chunked"`
+}
+
+type Score struct {
+	Points int    `aero:"points"`
+	Grade  string `aero:"grade"`
+	Note   string
+}
+
+type Leaderboard struct {
+	Scores  map[string]int                   `aero:"scores,ordered"`
+	Ranks   mapper.OrderedMap[int, string]   `aero:"ranks"`
+	Players map[string]Score                 `aero:"players"`
+	Levels  mapper.OrderedMap[string, Score] `aero:"levels"`
+}
+
+type Place struct {
+	Name     string          `aero:"name"`
+	Location mapper.GeoJSON  `aero:"location"`
+	Area     *mapper.GeoJSON `aero:"area"`
+	Visitors mapper.HLL      `aero:"visitors"`
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+
+	"github.com/reugn/aerospike-mapper-go/particle"
 )
 
 // MapPair is used when the client returns sorted maps from the server
@@ -376,6 +378,17 @@ func NewRawBlobValue(pt int, b []byte) *RawBlobValue {
 	return &RawBlobValue{ParticleType: pt, Data: data}
 }
 
+// EncodeRawBlobValue generates a RawBlobValue instance holding the particle encoding of v.
+// If the encoding returns an err, it will panic.
+func EncodeRawBlobValue(v interface{}) *RawBlobValue {
+	particleType, data, err := particle.Encode(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return NewRawBlobValue(int(particleType), data)
+}
+
 // GetObject returns original value as an interface{}.
 func (vl *RawBlobValue) GetObject() interface{} {
 	return vl.Data
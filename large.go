@@ -0,0 +1,407 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// chunksBin is the manifest bin that maps the chunked bin names to the number
+	// of their chunks.
+	chunksBin = "@chunks"
+	// chunkIndexBin is the chunk record bin that holds the index of the chunk.
+	chunkIndexBin = "@chunk"
+)
+
+// LargeObject is a value encoded across multiple records by EncodeLarge.
+type LargeObject struct {
+	// Manifest is the record of the value, with the chunked bins replaced by the
+	// number of their chunks.
+	Manifest *Record
+	// Chunks contains the chunk records of the chunked bins.
+	Chunks []*Record
+}
+
+// ChunkKey identifies a chunk record of a LargeObject.
+type ChunkKey struct {
+	Key
+	KeyValue
+}
+
+// EncodeLarge encodes v into a LargeObject, splitting the values of the fields
+// tagged with the `chunked` option into chunk records of at most chunkSize bytes.
+// See Mapper.EncodeLarge for details.
+func EncodeLarge(v any, chunkSize int) (*LargeObject, error) {
+	return defaultMapper.EncodeLarge(v, chunkSize)
+}
+
+// DecodeLarge reassembles the manifest and the chunk records into v.
+// See Mapper.DecodeLarge for details.
+func DecodeLarge(manifest any, chunks []any, v any) error {
+	return defaultMapper.DecodeLarge(manifest, chunks, v)
+}
+
+// EncodeLarge encodes v into a LargeObject. The []byte, string and slice values of
+// the fields tagged with the `chunked` option, e.g. `aero:"events,chunked"`, are
+// split into chunk records of at most chunkSize estimated bytes (a slice element is
+// never split). The chunk records are stored in the namespace and set of the manifest,
+// with the user key "<user key>:<bin name>:<chunk index>".
+// The user key of v must be set to derive the chunk keys. If the Mapper is configured
+// using WithMaxRecordSize, the limit applies to the manifest and to every chunk record.
+func (m *Mapper) EncodeLarge(v any, chunkSize int) (*LargeObject, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidChunkSize, chunkSize)
+	}
+
+	manifest := &Record{
+		Bins: make(map[string]any),
+	}
+	if err := m.encodeInto(v, manifest, nil); err != nil {
+		return nil, err
+	}
+	if manifest.UserKey == nil {
+		return nil, fmt.Errorf("%w: large object requires a user key", ErrInvalidUserKey)
+	}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var binNames []string
+	if err := collectChunkedBinNames(t, &binNames, make(map[reflect.Type]struct{})); err != nil {
+		return nil, err
+	}
+
+	largeObject := &LargeObject{Manifest: manifest}
+	counts := make(map[string]int, len(binNames))
+	for _, binName := range binNames {
+		binValue, ok := manifest.Bins[binName]
+		if !ok {
+			continue
+		}
+		delete(manifest.Bins, binName)
+
+		parts, err := splitValue(reflect.ValueOf(binValue), chunkSize)
+		if err != nil {
+			return nil, fmt.Errorf("bin %s: %w", binName, err)
+		}
+
+		keys, err := chunkKeys(manifest, binName, len(parts))
+		if err != nil {
+			return nil, err
+		}
+		for i, part := range parts {
+			largeObject.Chunks = append(largeObject.Chunks, &Record{
+				Key:      keys[i].Key,
+				KeyValue: keys[i].KeyValue,
+				Metadata: Metadata{Expiration: manifest.Expiration},
+				Bins: map[string]any{
+					binName:       part,
+					chunkIndexBin: i,
+				},
+			})
+		}
+		counts[binName] = len(parts)
+	}
+	manifest.Bins[chunksBin] = counts
+
+	// check the record sizes after splitting the chunked bins
+	if err := m.checkSize(manifest); err != nil {
+		return nil, err
+	}
+	for _, chunk := range largeObject.Chunks {
+		if err := m.checkSize(chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	return largeObject, nil
+}
+
+// DecodeLarge reassembles the manifest and the chunk records into v.
+// The records can be Aerospike records or Records, in any order. Use ChunkKeys to
+// obtain the keys of the chunk records to read. Returns ErrMissingChunk if any of
+// the chunks is missing, and ErrInvalidSource if the chunk counts of the manifest are
+// negative or exceed the number of the chunk records.
+func (m *Mapper) DecodeLarge(manifest any, chunks []any, v any) error {
+	var merged Record
+	if err := m.decodeToRecord(manifest, &merged); err != nil {
+		return err
+	}
+
+	counts, err := chunkCounts(&merged)
+	if err != nil {
+		return err
+	}
+	delete(merged.Bins, chunksBin)
+
+	parts := make(map[string][]reflect.Value, len(counts))
+	for binName, count := range counts {
+		// each chunk record holds at most one chunk of the bin
+		if count > len(chunks) {
+			return fmt.Errorf("%w: %d chunks of bin %s, %d chunk records", ErrInvalidSource,
+				count, binName, len(chunks))
+		}
+		parts[binName] = make([]reflect.Value, count)
+	}
+
+	for _, chunk := range chunks {
+		var chunkRecord Record
+		if err := m.decodeToRecord(chunk, &chunkRecord); err != nil {
+			return err
+		}
+
+		var index int
+		if err := setIntegerValue(reflect.ValueOf(&index).Elem(),
+			reflect.ValueOf(chunkRecord.Bins[chunkIndexBin])); err != nil {
+			return fmt.Errorf("chunk index: %w", err)
+		}
+		for binName, binValue := range chunkRecord.Bins {
+			binParts, ok := parts[binName]
+			if !ok || index < 0 || index >= len(binParts) {
+				continue
+			}
+			binParts[index] = reflect.ValueOf(binValue)
+		}
+	}
+
+	for binName, binParts := range parts {
+		joined, err := joinValues(binParts)
+		if err != nil {
+			return fmt.Errorf("bin %s: %w", binName, err)
+		}
+		merged.Bins[binName] = joined
+	}
+
+	return m.Decode(&merged, v)
+}
+
+// ChunkKeys returns the keys of the chunk records of the manifest, which can be an
+// Aerospike record or a Record. The manifest record must contain the user key,
+// which requires the record to be written with the key sent to the server.
+func ChunkKeys(manifest any) ([]ChunkKey, error) {
+	var record Record
+	if err := Decode(manifest, &record); err != nil {
+		return nil, err
+	}
+
+	counts, err := chunkCounts(&record)
+	if err != nil {
+		return nil, err
+	}
+
+	binNames := make([]string, 0, len(counts))
+	for binName := range counts {
+		binNames = append(binNames, binName)
+	}
+	sort.Strings(binNames)
+
+	var keys []ChunkKey
+	for _, binName := range binNames {
+		binKeys, err := chunkKeys(&record, binName, counts[binName])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, binKeys...)
+	}
+
+	return keys, nil
+}
+
+// chunkCounts returns the number of chunks of the chunked bins of the manifest.
+func chunkCounts(manifest *Record) (map[string]int, error) {
+	binValue, ok := manifest.Bins[chunksBin]
+	if !ok {
+		return nil, fmt.Errorf("%w: no chunks manifest", ErrInvalidSource)
+	}
+
+	countsValue, err := convertElementType(binValue, reflect.TypeOf(map[string]int{}))
+	if err != nil {
+		return nil, fmt.Errorf("chunks manifest: %w", err)
+	}
+
+	counts := countsValue.Interface().(map[string]int)
+	for binName, count := range counts {
+		if count < 0 {
+			return nil, fmt.Errorf("%w: negative chunk count %d of bin %s", ErrInvalidSource,
+				count, binName)
+		}
+	}
+
+	return counts, nil
+}
+
+// chunkKeys returns the keys of the chunk records of the bin.
+func chunkKeys(manifest *Record, binName string, count int) ([]ChunkKey, error) {
+	if manifest.UserKey == nil {
+		return nil, fmt.Errorf("%w: large object requires a user key", ErrInvalidUserKey)
+	}
+
+	keys := make([]ChunkKey, count)
+	for i := range keys {
+		userKey := fmt.Sprintf("%v:%s:%d", manifest.UserKey, binName, i)
+		digest, err := ComputeDigest(manifest.SetName, userKey)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = ChunkKey{
+			Key: Key{
+				Namespace: manifest.Namespace,
+				SetName:   manifest.SetName,
+				Digest:    digest,
+			},
+			KeyValue: KeyValue{UserKey: userKey},
+		}
+	}
+
+	return keys, nil
+}
+
+// collectChunkedBinNames appends the names of the bins of the fields tagged with
+// the `chunked` option of the struct type t to binNames. The visiting map is used
+// to guard against recursive types.
+func collectChunkedBinNames(t reflect.Type, binNames *[]string,
+	visiting map[reflect.Type]struct{}) error {
+	if _, ok := visiting[t]; ok {
+		return nil
+	}
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	fields, err := cachedFields(t)
+	if err != nil {
+		return err
+	}
+
+	for i := range fields {
+		tag := fields[i].tag
+		fieldType := t.Field(i).Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && !tag.serialized() &&
+			fieldType.String() != timeType {
+			if err := collectChunkedBinNames(fieldType, binNames, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fields[i].tagged && tag.chunked && !tag.meta && !tag.omit && tag.name != "" {
+			*binNames = append(*binNames, tag.name)
+		}
+	}
+
+	return nil
+}
+
+// runeBoundary returns the index of the rune start in s at or before end, so that
+// s[start:end] does not split a rune. If the first rune does not fit, the index
+// after it is returned.
+func runeBoundary(s string, start, end int) int {
+	if end == len(s) {
+		return end
+	}
+	for i := end; i > start; i-- {
+		if utf8.RuneStart(s[i]) {
+			return i
+		}
+	}
+	_, size := utf8.DecodeRuneInString(s[start:])
+	return start + size
+}
+
+// splitValue splits the []byte, string or slice value v into parts of at most
+// chunkSize estimated bytes. Strings are split on rune boundaries, so that each
+// part is valid UTF-8. Nil values result in no parts.
+func splitValue(v reflect.Value, chunkSize int) ([]any, error) {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	var parts []any
+	switch {
+	case !v.IsValid():
+		return nil, nil
+	case v.Kind() == reflect.String:
+		s := v.String()
+		for start := 0; start < len(s); {
+			end := runeBoundary(s, start, minInt(start+chunkSize, len(s)))
+			parts = append(parts, s[start:end])
+			start = end
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		b := v.Bytes()
+		for start := 0; start < len(b); start += chunkSize {
+			parts = append(parts, b[start:minInt(start+chunkSize, len(b))])
+		}
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		var part []any
+		size := 0
+		for i := 0; i < v.Len(); i++ {
+			element := v.Index(i)
+			elementSize := msgpackSize(element)
+			if len(part) > 0 && size+elementSize > chunkSize {
+				parts = append(parts, part)
+				part, size = nil, 0
+			}
+			part = append(part, element.Interface())
+			size += elementSize
+		}
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	default:
+		return nil, fmt.Errorf("cannot split %s: []byte, string or slice required", v.Type())
+	}
+
+	return parts, nil
+}
+
+// joinValues joins the parts produced by splitValue.
+func joinValues(parts []reflect.Value) (any, error) {
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	for i, part := range parts {
+		if !part.IsValid() {
+			return nil, fmt.Errorf("%w: index %d", ErrMissingChunk, i)
+		}
+	}
+
+	switch first := parts[0]; {
+	case first.Kind() == reflect.String:
+		var joined []byte
+		for _, part := range parts {
+			joined = append(joined, part.String()...)
+		}
+		return string(joined), nil
+	case first.Kind() == reflect.Slice && first.Type().Elem().Kind() == reflect.Uint8:
+		var joined []byte
+		for _, part := range parts {
+			joined = append(joined, part.Bytes()...)
+		}
+		return joined, nil
+	case first.Kind() == reflect.Slice:
+		var joined []any
+		for _, part := range parts {
+			for i := 0; i < part.Len(); i++ {
+				joined = append(joined, part.Index(i).Interface())
+			}
+		}
+		return joined, nil
+	default:
+		return nil, fmt.Errorf("cannot join %s", first.Type())
+	}
+}
+
+// minInt returns the smaller of a and b.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
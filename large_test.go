@@ -0,0 +1,137 @@
+package mapper_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func newTimeline() testtypes.Timeline {
+	events := make([]string, 10)
+	for i := range events {
+		events[i] = strings.Repeat(string(rune('a'+i)), 20)
+	}
+	return testtypes.Timeline{
+		Key:      mapper.Key{Namespace: "ns1", SetName: "set1"},
+		KeyValue: mapper.KeyValue{UserKey: "key1"},
+		Title:    "title1",
+		Events:   events,
+		Blob:     bytes.Repeat([]byte{1}, 250),
+	}
+}
+
+func TestMapper_EncodeLarge(t *testing.T) {
+	timeline := newTimeline()
+
+	largeObject, err := mapper.EncodeLarge(&timeline, 100)
+	assert.IsNil(t, err)
+	assert.Equal(t, largeObject.Manifest.Bins, map[string]any{
+		"title":   "title1",
+		"@chunks": map[string]int{"events": 3, "blob": 3},
+	})
+	assert.Equal(t, len(largeObject.Chunks), 6)
+
+	chunk := largeObject.Chunks[0]
+	assert.Equal(t, chunk.UserKey, any("key1:events:0"))
+	assert.Equal(t, chunk.Namespace, "ns1")
+	assert.Equal(t, chunk.SetName, "set1")
+	digest, err := mapper.ComputeDigest("set1", "key1:events:0")
+	assert.IsNil(t, err)
+	assert.Equal(t, chunk.Digest, digest)
+	assert.Equal(t, len(chunk.Bins["events"].([]any)), 4)
+	assert.Equal(t, chunk.Bins["@chunk"], any(0))
+
+	keys, err := mapper.ChunkKeys(largeObject.Manifest)
+	assert.IsNil(t, err)
+	assert.Equal(t, len(keys), 6)
+	assert.Equal(t, keys[0].UserKey, any("key1:blob:0"))
+	assert.Equal(t, keys[5].UserKey, any("key1:events:2"))
+	for _, chunk := range largeObject.Chunks {
+		assert.Equal(t, mapper.EstimateSize(chunk) < 250, true)
+	}
+
+	// reassemble from the client records in any order
+	chunks := make([]any, len(largeObject.Chunks))
+	for i, chunk := range largeObject.Chunks {
+		chunks[len(chunks)-1-i] = &testtypes.Record{Bins: testtypes.BinMap(chunk.Bins)}
+	}
+
+	var decoded testtypes.Timeline
+	err = mapper.DecodeLarge(largeObject.Manifest, chunks, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, timeline)
+
+	err = mapper.DecodeLarge(largeObject.Manifest, chunks[1:], &decoded)
+	assert.ErrorIs(t, err, mapper.ErrMissingChunk)
+}
+
+func TestMapper_EncodeLargeString(t *testing.T) {
+	type article struct {
+		mapper.Key
+		mapper.KeyValue
+		Body string `aero:"body,chunked"`
+	}
+
+	// the multi-byte runes are not split across the chunks
+	article1 := article{
+		Key:      mapper.Key{Namespace: "ns1", SetName: "set1"},
+		KeyValue: mapper.KeyValue{UserKey: "key1"},
+		Body:     strings.Repeat("aé€😀", 10),
+	}
+	for _, chunkSize := range []int{1, 3, 5, 8} {
+		largeObject, err := mapper.EncodeLarge(&article1, chunkSize)
+		assert.IsNil(t, err)
+
+		chunks := make([]any, len(largeObject.Chunks))
+		for i, chunk := range largeObject.Chunks {
+			body := chunk.Bins["body"].(string)
+			assert.Equal(t, utf8.ValidString(body), true)
+			assert.Equal(t, len(body) <= chunkSize || utf8.RuneCountInString(body) == 1, true)
+			chunks[i] = chunk
+		}
+
+		var decoded article
+		err = mapper.DecodeLarge(largeObject.Manifest, chunks, &decoded)
+		assert.IsNil(t, err)
+		assert.Equal(t, decoded.Body, article1.Body)
+	}
+}
+
+func TestMapper_EncodeLargeErrors(t *testing.T) {
+	timeline := newTimeline()
+
+	m := mapper.New(mapper.WithMaxRecordSize(250))
+	_, err := m.EncodeLarge(&timeline, 100)
+	assert.IsNil(t, err)
+
+	_, err = m.EncodeLarge(&timeline, 500)
+	assert.ErrorIs(t, err, mapper.ErrRecordTooLarge)
+
+	_, err = mapper.EncodeLarge(&timeline, 0)
+	assert.ErrorIs(t, err, mapper.ErrInvalidChunkSize)
+
+	timeline.UserKey = nil
+	_, err = mapper.EncodeLarge(&timeline, 100)
+	assert.ErrorIs(t, err, mapper.ErrInvalidUserKey)
+
+	var decoded testtypes.Timeline
+	err = mapper.DecodeLarge(&mapper.Record{}, nil, &decoded)
+	assert.ErrorIs(t, err, mapper.ErrInvalidSource)
+
+	// corrupted manifests
+	for _, counts := range []map[any]any{{"events": -1}, {"events": 1 << 40}} {
+		manifest := &testtypes.Record{Bins: testtypes.BinMap{"@chunks": counts}}
+		err = mapper.DecodeLarge(manifest, nil, &decoded)
+		assert.ErrorIs(t, err, mapper.ErrInvalidSource)
+	}
+
+	_, err = mapper.ChunkKeys(&testtypes.Record{
+		Bins: testtypes.BinMap{"@chunks": map[any]any{"events": -1}},
+	})
+	assert.ErrorIs(t, err, mapper.ErrInvalidSource)
+}
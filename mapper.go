@@ -3,25 +3,45 @@ package mapper
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // metadata tag values
 const (
 	metaTagGeneration = "generation"
 	metaTagExpiration = "expiration"
+	metaTagLastUpdate = "last_update"
+	metaTagVoidTime   = "void_time"
+	metaTagSize       = "size"
 	metaTagNamespace  = "namespace"
 	metaTagSetName    = "set_name"
 	metaTagDigest     = "digest"
 	metaTagUserKey    = "user_key"
 )
 
+// optionalRecordFields maps the metadata tag values to the names of the source record
+// fields, which are decoded only if the source record exposes them.
+var optionalRecordFields = map[string]string{
+	metaTagLastUpdate: "LastUpdate",
+	metaTagVoidTime:   "VoidTime",
+	metaTagSize:       "Size",
+}
+
 const (
 	mapperTag         = "aero"
 	tagValueMeta      = "meta"
 	tagValueOmit      = "omit"
 	tagValueOmitempty = "omitempty"
+	tagValueNullable  = "nullable"
+	tagValueEncrypt   = "encrypt"
+	tagValueCompress  = "compress"
+	tagValueJSON      = "json"
+	tagValueGob       = "gob"
+	tagValueChunked   = "chunked"
+	tagValueOrdered   = "ordered"
 
 	timeType = "time.Time"
 )
@@ -40,10 +60,39 @@ type tag struct {
 	// omitempty indicates that the field should be omitted from the Aerospike record if it is
 	// empty (zero value).
 	omitempty bool
+	// nullable indicates that the field should be encoded as a nil bin if it is empty,
+	// which deletes the bin on write.
+	nullable bool
+	// encrypt indicates that the field value should be encrypted using the Mapper Cipher.
+	encrypt bool
+	// compress indicates that the field value should be compressed, if it exceeds the
+	// Mapper compression threshold.
+	compress bool
+	// json indicates that the field value should be serialized using encoding/json
+	// into a string bin.
+	json bool
+	// gob indicates that the field value should be serialized using encoding/gob
+	// into a []byte bin.
+	gob bool
+	// chunked indicates that the field value should be split into chunk records
+	// by EncodeLarge.
+	chunked bool
+	// ordered indicates that the map field value should be encoded as a key-ordered
+	// Aerospike map.
+	ordered bool
 	// name is the bin name to use for the field.
 	name string
+	// rules contains the validation rules of the field, e.g. "max=150".
+	rules []string
 }
 
+// deleteBinValue is the type of the DeleteBin sentinel.
+type deleteBinValue struct{}
+
+// DeleteBin is a sentinel value that can be assigned to an `any` field to encode it
+// as a nil bin, which deletes the bin on write.
+var DeleteBin = deleteBinValue{}
+
 // Record is the Aerospike record representation produced by the Encode operation.
 // It contains the key, metadata, and bin data.
 type Record struct {
@@ -57,85 +106,188 @@ type Record struct {
 	Bins map[string]any
 }
 
+// NamespaceProvider is an optional interface to be implemented by the data model types
+// to specify the default namespace of their records.
+// It is consulted by Encode when the namespace is not mapped or empty.
+type NamespaceProvider interface {
+	AerospikeNamespace() string
+}
+
+// SetProvider is an optional interface to be implemented by the data model types
+// to specify the default set name of their records.
+// It is consulted by Encode when the set name is not mapped or empty.
+type SetProvider interface {
+	AerospikeSet() string
+}
+
 // Encode encodes v into a Record.
 // v must be a struct or struct pointer with fields tagged using the `aero` tag
 // to specify how they should be mapped to the record.
+// The BeforeEncoder and Validator hooks of v and its nested structs are called
+// before encoding, and the field values are checked against the tag validation rules.
 func Encode(v any) (*Record, error) {
+	return defaultMapper.Encode(v)
+}
+
+// EncodeInto encodes v into the given record, which is reset before encoding.
+// The bins map of the record is cleared and reused to avoid allocations, which makes
+// EncodeInto suitable for encoding many values with a single record, or with records
+// obtained from a RecordPool.
+// v must be a struct or struct pointer with fields tagged using the `aero` tag.
+func EncodeInto(v any, record *Record) error {
+	return defaultMapper.EncodeInto(v, record)
+}
+
+// Encode encodes v into a Record using the Mapper configuration.
+// See the package level Encode function for details.
+func (m *Mapper) Encode(v any) (*Record, error) {
 	// initialize the return record value
 	record := &Record{
 		Bins: make(map[string]any),
 	}
+	if err := m.EncodeInto(v, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// EncodeInto encodes v into the given record using the Mapper configuration.
+// See the package level EncodeInto function for details.
+func (m *Mapper) EncodeInto(v any, record *Record) error {
+	if err := m.encodeInto(v, record, nil); err != nil {
+		return err
+	}
+
+	return m.checkSize(record)
+}
+
+// encodeInto encodes v into the given record without checking the record size.
+// If plain is not nil, the values of the transformed bins are stored in it before
+// being transformed.
+func (m *Mapper) encodeInto(v any, record *Record, plain map[string]any) error {
+	// call the lifecycle hooks
+	v, err := beforeEncode(v)
+	if err != nil {
+		return err
+	}
+	// check the tag validation rules
+	if err := Validate(v); err != nil {
+		return err
+	}
+
+	return m.encodeBins(v, record, plain)
+}
+
+// encodeBins encodes v into the given record as encodeInto does, without calling
+// the lifecycle hooks and checking the validation rules.
+func (m *Mapper) encodeBins(v any, record *Record, plain map[string]any) error {
+	resetRecord(record)
+
 	// call the recursive encode function
-	return encode(v, record)
+	if _, err := m.encode(v, record, plain); err != nil {
+		return err
+	}
+	// set the default namespace and set name if not specified
+	setKeyDefaults(v, record)
+
+	return nil
+}
+
+// setKeyDefaults sets the record namespace and set name using the NamespaceProvider
+// and SetProvider implementations of v, if they are not already set.
+func setKeyDefaults(v any, record *Record) {
+	if record.Namespace == "" {
+		if provider, ok := asInterface[NamespaceProvider](v); ok {
+			record.Namespace = provider.AerospikeNamespace()
+		}
+	}
+	if record.SetName == "" {
+		if provider, ok := asInterface[SetProvider](v); ok {
+			record.SetName = provider.AerospikeSet()
+		}
+	}
 }
 
 // encode recursively encodes v and returns the encoded record.
-//
-//nolint:funlen
-func encode(v any, record *Record) (*Record, error) {
+func (m *Mapper) encode(v any, record *Record, plain map[string]any) (*Record, error) {
 	sourceValue, err := structValue(v)
 	if err != nil {
 		return nil, err
 	}
 
-	sourceType := sourceValue.Type()
-	for i := 0; i < sourceType.NumField(); i++ {
+	if err := m.encodeStruct(sourceValue, record, plain); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// encodeStruct recursively encodes the struct value into the record.
+// If plain is not nil, the values of the transformed bins are stored in it before
+// being transformed.
+//
+//nolint:funlen,gocyclo
+func (m *Mapper) encodeStruct(sourceValue reflect.Value, record *Record,
+	plain map[string]any) error {
+	fields, err := cachedFields(sourceValue.Type())
+	if err != nil {
+		return err
+	}
+
+	for i := range fields {
 		fieldValue := fieldValueDeref(sourceValue, i)
-		if fieldValue.Kind() == reflect.Struct {
-			_, err := encode(fieldValue, record)
-			if err != nil {
-				return nil, err
+		if fieldValue.Kind() == reflect.Struct && !fields[i].tag.serialized() &&
+			fieldValue.Type().String() != timeType {
+			if err := m.encodeStruct(fieldValue, record, plain); err != nil {
+				return err
 			}
 			continue
 		}
 
-		aeroTag := sourceType.Field(i).Tag.Get(mapperTag)
-		if aeroTag == "" {
+		field := &fields[i]
+		if !field.tagged {
 			continue
 		}
 
-		tag, err := parseTag(aeroTag)
-		if err != nil {
-			return nil, err
-		}
-
+		tag := field.tag
 		if tag.meta {
 			switch tag.name {
 			case metaTagGeneration:
 				err := setMetadata(fieldValue,
-					reflect.ValueOf(&record.Metadata).Elem().FieldByName("Generation"), tag.name)
+					reflect.ValueOf(&record.Generation).Elem(), tag.name)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			case metaTagExpiration:
 				err := setMetadata(fieldValue,
-					reflect.ValueOf(&record.Metadata).Elem().FieldByName("Expiration"), tag.name)
+					reflect.ValueOf(&record.Expiration).Elem(), tag.name)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			case metaTagNamespace:
 				err := setMetadata(fieldValue,
-					reflect.ValueOf(&record.Key).Elem().FieldByName("Namespace"), tag.name)
+					reflect.ValueOf(&record.Namespace).Elem(), tag.name)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			case metaTagSetName:
 				err := setMetadata(fieldValue,
-					reflect.ValueOf(&record.Key).Elem().FieldByName("SetName"), tag.name)
+					reflect.ValueOf(&record.SetName).Elem(), tag.name)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			case metaTagDigest:
 				err := setMetadata(fieldValue,
-					reflect.ValueOf(&record.Key).Elem().FieldByName("Digest"), tag.name)
+					reflect.ValueOf(&record.Digest).Elem(), tag.name)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			case metaTagUserKey:
 				err := setMetadata(fieldValue,
-					reflect.ValueOf(&record.KeyValue).Elem().FieldByName("UserKey"), tag.name)
+					reflect.ValueOf(&record.UserKey).Elem(), tag.name)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 		} else {
@@ -149,15 +301,32 @@ func encode(v any, record *Record) (*Record, error) {
 				// binName = sourceType.Field(i).Name
 				continue
 			}
-			if empty {
-				record.Bins[binName] = reflect.Zero(sourceType.Field(i).Type).Interface()
-			} else {
-				record.Bins[binName] = fieldValue.Interface()
+			var binValue any
+			switch {
+			case empty && tag.nullable, isDeleteBin(fieldValue):
+				record.Bins[binName] = nil
+				continue
+			case empty:
+				binValue = field.zero
+			default:
+				binValue = fieldValue.Interface()
 			}
+			if binValue, err = encodeOrdered(&tag, binValue); err != nil {
+				return fmt.Errorf("field %s: %w", sourceValue.Type().Field(i).Name, err)
+			}
+			if tag.transformed() {
+				if plain != nil {
+					plain[binName] = binValue
+				}
+				if binValue, err = m.encodeValue(&tag, binValue); err != nil {
+					return fmt.Errorf("field %s: %w", sourceValue.Type().Field(i).Name, err)
+				}
+			}
+			record.Bins[binName] = encodeParticle(binValue)
 		}
 	}
 
-	return record, nil
+	return nil
 }
 
 // setMetadata is a helper function to set record metadata fields.
@@ -179,14 +348,99 @@ func setMetadata(field reflect.Value, recordField reflect.Value, tagName string)
 	return errors.New("cannot set record field")
 }
 
-// Decode decodes an aerospike record or a record containing struct into v.
+// Reserved keys used to store the record metadata when decoding into a map[string]any.
+const (
+	MapKeyNamespace  = "@" + metaTagNamespace
+	MapKeySetName    = "@" + metaTagSetName
+	MapKeyDigest     = "@" + metaTagDigest
+	MapKeyUserKey    = "@" + metaTagUserKey
+	MapKeyGeneration = "@" + metaTagGeneration
+	MapKeyExpiration = "@" + metaTagExpiration
+)
+
+var recordType = reflect.TypeOf(Record{})
+
+// Decode decodes an aerospike record, a record containing struct, or a Record produced
+// by Encode into v.
+// v must be a pointer to a struct, a pointer to a Record, or a pointer to a map[string]any.
+// When decoding into a map, the record metadata is stored using the reserved MapKey* keys.
+// The AfterDecoder hooks of the target struct and its nested structs are called after decoding.
 func Decode(record, v any) error {
+	return defaultMapper.Decode(record, v)
+}
+
+// Decode decodes the record into v using the Mapper configuration.
+// See the package level Decode function for details.
+func (m *Mapper) Decode(record, v any) error {
+	switch target := v.(type) {
+	case *Record:
+		return m.decodeToRecord(record, target)
+	case *map[string]any:
+		return m.decodeToMap(record, target)
+	default:
+		if err := m.decode(record, v); err != nil {
+			return err
+		}
+		return afterDecode(v)
+	}
+}
+
+// decodeToRecord decodes an aerospike record into the target Record.
+func (m *Mapper) decodeToRecord(record any, target *Record) error {
+	*target = Record{
+		Bins: make(map[string]any),
+	}
+	return m.decode(record, target)
+}
+
+// decodeToMap decodes an aerospike record into the target map, with the record
+// metadata stored using the reserved keys.
+func (m *Mapper) decodeToMap(record any, target *map[string]any) error {
+	var decoded Record
+	if err := m.decodeToRecord(record, &decoded); err != nil {
+		return err
+	}
+
+	bins := make(map[string]any, len(decoded.Bins)+6)
+	for binName, binValue := range decoded.Bins {
+		bins[binName] = binValue
+	}
+
+	if decoded.Namespace != "" {
+		bins[MapKeyNamespace] = decoded.Namespace
+	}
+	if decoded.SetName != "" {
+		bins[MapKeySetName] = decoded.SetName
+	}
+	if decoded.Digest != [20]byte{} {
+		bins[MapKeyDigest] = decoded.Digest
+	}
+	if decoded.UserKey != nil {
+		bins[MapKeyUserKey] = decoded.UserKey
+	}
+	if decoded.Generation != 0 {
+		bins[MapKeyGeneration] = decoded.Generation
+	}
+	if decoded.Expiration != 0 {
+		bins[MapKeyExpiration] = decoded.Expiration
+	}
+
+	*target = bins
+	return nil
+}
+
+// decode decodes an aerospike record or a record containing struct into v.
+func (m *Mapper) decode(record, v any) error {
 	_, inner := record.(reflect.Value)
 	recordValue, err := structValue(record)
 	if err != nil {
 		return err
 	}
 
+	if recordValue.Type() == recordType {
+		return m.decodeMapperRecord(recordValue, v)
+	}
+
 	var isRecord bool
 	recordType := recordValue.Type()
 	for i := 0; i < recordType.NumField(); i++ {
@@ -196,7 +450,7 @@ func Decode(record, v any) error {
 		switch {
 		case fieldValue.Kind() == reflect.Struct && fieldName == "BatchRecord":
 			isRecord = true
-			if err := Decode(fieldValue, v); err != nil {
+			if err := m.decode(fieldValue, v); err != nil {
 				return err
 			}
 		case fieldValue.Kind() == reflect.Struct && fieldName == "Record":
@@ -204,7 +458,7 @@ func Decode(record, v any) error {
 			if err := decodeRecord(fieldValue, v); err != nil {
 				return err
 			}
-			if err := Decode(fieldValue, v); err != nil {
+			if err := m.decode(fieldValue, v); err != nil {
 				return err
 			}
 		case fieldValue.Kind() == reflect.Struct && fieldName == "Key":
@@ -213,7 +467,7 @@ func Decode(record, v any) error {
 			}
 		case fieldValue.Kind() == reflect.Map && fieldName == "Bins":
 			isRecord = true
-			if err := decodeBins(fieldValue, v); err != nil {
+			if err := m.decodeBins(fieldValue, v); err != nil {
 				return err
 			}
 		case !inner && fieldValue.Kind() == reflect.Uint32 &&
@@ -231,11 +485,28 @@ func Decode(record, v any) error {
 	return nil
 }
 
-func decodeBins(recordValue reflect.Value, v any) error {
-	if recordValue.Kind() != reflect.Map {
-		return nil // continue
+// decodeMapperRecord decodes a Record, typically produced by Encode, into v.
+func (m *Mapper) decodeMapperRecord(recordValue reflect.Value, v any) error {
+	record := recordValue.Interface().(Record)
+	metaValues := map[string]reflect.Value{
+		metaTagNamespace:  reflect.ValueOf(record.Namespace),
+		metaTagSetName:    reflect.ValueOf(record.SetName),
+		metaTagDigest:     reflect.ValueOf(record.Digest),
+		metaTagUserKey:    reflect.ValueOf(record.UserKey),
+		metaTagGeneration: reflect.ValueOf(record.Generation),
+		metaTagExpiration: reflect.ValueOf(record.Expiration),
+	}
+
+	if err := decodeMeta(metaValues, v); err != nil {
+		return err
 	}
 
+	return m.decodeBins(reflect.ValueOf(record.Bins), v)
+}
+
+// decodeMeta sets the metadata fields of v using the values mapped by the
+// metadata tag values. Empty source values are ignored.
+func decodeMeta(metaValues map[string]reflect.Value, v any) error {
 	targetValue, err := structValue(v)
 	if err != nil {
 		return err
@@ -244,8 +515,8 @@ func decodeBins(recordValue reflect.Value, v any) error {
 	targetType := targetValue.Type()
 	for i := 0; i < targetType.NumField(); i++ {
 		fieldValue := fieldValueDeref(targetValue, i)
-		if fieldValue.Kind() == reflect.Struct {
-			if err := decodeBins(recordValue, fieldValue); err != nil {
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type().String() != timeType {
+			if err := decodeMeta(metaValues, fieldValue); err != nil {
 				return err
 			}
 			continue
@@ -262,7 +533,99 @@ func decodeBins(recordValue reflect.Value, v any) error {
 			return err
 		}
 
-		if tag.name == "" {
+		if !tag.meta {
+			continue
+		}
+
+		metaValue, ok := metaValues[tag.name]
+		if !ok || isEmptyValue(metaValue) || isEmptyArray(metaValue) {
+			continue
+		}
+
+		if !fieldValue.CanSet() {
+			return fmt.Errorf("cannot set %s", tag.name)
+		}
+
+		if err := setMetaValue(fieldValue, metaValue); err != nil {
+			return fmt.Errorf("%s: %w", tag.name, err)
+		}
+	}
+
+	return nil
+}
+
+// setMetaValue sets the metadata field value, converting the source value
+// to the field type if required.
+func setMetaValue(fieldValue, metaValue reflect.Value) error {
+	if metaValue.Kind() == reflect.Interface {
+		metaValue = metaValue.Elem()
+	}
+
+	fieldType := fieldValue.Type()
+	switch {
+	case metaValue.Type().AssignableTo(fieldType):
+		fieldValue.Set(metaValue)
+	case metaValue.Kind() == reflect.Array && fieldType.Kind() == reflect.Slice &&
+		metaValue.Type().Elem() == fieldType.Elem():
+		slice := reflect.MakeSlice(fieldType, metaValue.Len(), metaValue.Len())
+		reflect.Copy(slice, metaValue)
+		fieldValue.Set(slice)
+	case isIntegerKind(fieldType.Kind()) && isIntegerKind(metaValue.Kind()):
+		return setIntegerValue(fieldValue, metaValue)
+	default:
+		convertedValue, err := convertElementType(metaValue, fieldType)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(convertedValue)
+	}
+
+	return nil
+}
+
+func (m *Mapper) decodeBins(recordValue reflect.Value, v any) error {
+	if recordValue.Kind() != reflect.Map {
+		return nil // continue
+	}
+
+	targetValue, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	targetType := targetValue.Type()
+	if targetType == recordType {
+		// copy all bins into the target record
+		bins := targetValue.FieldByName("Bins")
+		for _, binName := range recordValue.MapKeys() {
+			if binName.Kind() == reflect.Interface {
+				binName = binName.Elem()
+			}
+			if binName.Kind() != reflect.String {
+				return fmt.Errorf("unsupported bin name type: %s", binName.Type())
+			}
+			bins.SetMapIndex(reflect.ValueOf(binName.String()), recordValue.MapIndex(binName))
+		}
+		return nil
+	}
+
+	fields, err := cachedFields(targetType)
+	if err != nil {
+		return err
+	}
+
+	for i := range fields {
+		tag := fields[i].tag
+		fieldValue := fieldValueDeref(targetValue, i)
+		if fieldValue.Kind() == reflect.Struct && !tag.serialized() &&
+			fieldValue.Type().String() != timeType {
+			if err := m.decodeBins(recordValue, fieldValue); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !fields[i].tagged || tag.meta || tag.name == "" {
 			continue
 		}
 
@@ -271,11 +634,19 @@ func decodeBins(recordValue reflect.Value, v any) error {
 			continue
 		}
 
-		// check if the field can be set
+		// check if the field can be set; use the field itself rather than the
+		// dereferenced value to allocate nil pointers
+		fieldValue = targetValue.Field(i)
 		if !fieldValue.CanSet() {
 			continue
 		}
 
+		if tag.transformed() {
+			if binValue, err = m.decodeValue(&tag, binValue, fieldValue.Type()); err != nil {
+				return fmt.Errorf("field %s: %w", targetType.Field(i).Name, err)
+			}
+		}
+
 		// convert the source value to the correct type
 		convertedValue, err := convertElementType(binValue, targetType.Field(i).Type)
 		if err != nil {
@@ -458,7 +829,7 @@ func decodeRecord(recordValue reflect.Value, v any) error {
 	targetType := targetValue.Type()
 	for i := 0; i < targetType.NumField(); i++ {
 		fieldValue := fieldValueDeref(targetValue, i)
-		if fieldValue.Kind() == reflect.Struct {
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type().String() != timeType {
 			if err := decodeRecord(recordValue, fieldValue); err != nil {
 				return err
 			}
@@ -481,7 +852,7 @@ func decodeRecord(recordValue reflect.Value, v any) error {
 		}
 
 		switch tag.name {
-		case "generation":
+		case metaTagGeneration:
 			if !fieldValue.CanSet() {
 				return fmt.Errorf("cannot set %s", tag.name)
 			}
@@ -494,7 +865,7 @@ func decodeRecord(recordValue reflect.Value, v any) error {
 			if err := setIntegerValue(fieldValue, f); err != nil {
 				return fmt.Errorf("%s: %w", tag.name, err)
 			}
-		case "expiration":
+		case metaTagExpiration:
 			if !fieldValue.CanSet() {
 				return fmt.Errorf("cannot set %s", tag.name)
 			}
@@ -507,7 +878,85 @@ func decodeRecord(recordValue reflect.Value, v any) error {
 			if err := setIntegerValue(fieldValue, f); err != nil {
 				return fmt.Errorf("%s: %w", tag.name, err)
 			}
+		case metaTagLastUpdate, metaTagVoidTime:
+			if !fieldValue.CanSet() {
+				return fmt.Errorf("cannot set %s", tag.name)
+			}
+
+			f, err := getField(recordValue, optionalRecordFields[tag.name])
+			if err != nil {
+				continue // the source record does not expose the field
+			}
+
+			toTime := unixNanoTime
+			if tag.name == metaTagVoidTime {
+				toTime = voidTime
+			}
+			if err := setTimestampValue(fieldValue, f, toTime); err != nil {
+				return fmt.Errorf("%s: %w", tag.name, err)
+			}
+		case metaTagSize:
+			if !fieldValue.CanSet() {
+				return fmt.Errorf("cannot set %s", tag.name)
+			}
+
+			f, err := getField(recordValue, optionalRecordFields[tag.name])
+			if err != nil {
+				continue // the source record does not expose the field
+			}
+
+			if err := setIntegerValue(fieldValue, f); err != nil {
+				return fmt.Errorf("%s: %w", tag.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// citrineEpoch is the Aerospike epoch (2010-01-01 UTC), which the record void time
+// is relative to.
+var citrineEpoch = time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// unixNanoTime returns the time for the nanoseconds since the Unix epoch.
+func unixNanoTime(nanos int64) time.Time {
+	return time.Unix(0, nanos)
+}
+
+// voidTime returns the time for the record void time in seconds since the citrine
+// epoch. The zero void time, which means the record never expires, results in
+// the zero time.
+func voidTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return citrineEpoch.Add(time.Duration(seconds) * time.Second)
+}
+
+// setTimestampValue sets a timestamp field value. The integer source value is converted
+// to time.Time using the toTime function. If the field is not a time.Time, the value
+// is set as is using setIntegerValue.
+func setTimestampValue(fieldValue, recordValue reflect.Value, toTime func(int64) time.Time) error {
+	if fieldValue.Type().String() != timeType {
+		return setIntegerValue(fieldValue, recordValue)
+	}
+
+	switch recordValue.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		fieldValue.Set(reflect.ValueOf(toTime(recordValue.Int())))
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		if recordValue.Uint() > math.MaxInt64 {
+			return fmt.Errorf("value '%d' overflows destination type '%s'",
+				recordValue.Uint(), fieldValue.Type())
+		}
+		fieldValue.Set(reflect.ValueOf(toTime(int64(recordValue.Uint()))))
+	case reflect.Struct:
+		if recordValue.Type() != fieldValue.Type() {
+			return fmt.Errorf("unsupported source type: %s", recordValue.Type())
 		}
+		fieldValue.Set(recordValue)
+	default:
+		return fmt.Errorf("unsupported source type: %s", recordValue.Type())
 	}
 
 	return nil
@@ -518,23 +967,51 @@ func setIntegerValue(fieldValue, recordValue reflect.Value) error {
 	// determine the field Kind and convert accordingly to prevent overflow
 	switch fieldValue.Kind() {
 	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		var value int64
+		switch recordValue.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+			value = recordValue.Int()
+		case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+			if recordValue.Uint() > math.MaxInt64 {
+				return fmt.Errorf("value '%d' overflows destination type '%s'",
+					recordValue.Uint(), fieldValue.Type())
+			}
+			value = int64(recordValue.Uint())
+		default:
+			return fmt.Errorf("unsupported source type: %s", recordValue.Type())
+		}
+
 		// check if the source value is in the range of the destination type before
 		// conversion
-		if fieldValue.OverflowInt(recordValue.Int()) {
+		if fieldValue.OverflowInt(value) {
 			return fmt.Errorf("value '%d' overflows destination type '%s'",
-				recordValue.Int(), fieldValue.Type())
+				value, fieldValue.Type())
 		}
 
-		fieldValue.Set(reflect.ValueOf(recordValue.Convert(fieldValue.Type()).Interface()))
+		fieldValue.SetInt(value)
 	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		var value uint64
+		switch recordValue.Kind() {
+		case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+			value = recordValue.Uint()
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+			if recordValue.Int() < 0 {
+				return fmt.Errorf("value '%d' overflows destination type '%s'",
+					recordValue.Int(), fieldValue.Type())
+			}
+			value = uint64(recordValue.Int())
+		default:
+			return fmt.Errorf("unsupported source type: %s", recordValue.Type())
+		}
+
 		// check if the source value is in the range of the destination type before
 		// conversion
-		if fieldValue.OverflowUint(recordValue.Uint()) {
+		if fieldValue.OverflowUint(value) {
 			return fmt.Errorf("value '%d' overflows destination type '%s'",
-				recordValue.Int(), fieldValue.Type())
+				value, fieldValue.Type())
 		}
 
-		fieldValue.Set(reflect.ValueOf(recordValue.Convert(fieldValue.Type()).Interface()))
+		fieldValue.SetUint(value)
 	default:
 		return fmt.Errorf("unsupported field type: %s", fieldValue.Type())
 	}
@@ -556,11 +1033,29 @@ func parseTag(tagString string) (tag, error) {
 			parsed.omitempty = true
 		case tagValueOmit:
 			parsed.omit = true
+		case tagValueNullable:
+			parsed.nullable = true
+		case tagValueEncrypt:
+			parsed.encrypt = true
+		case tagValueCompress:
+			parsed.compress = true
+		case tagValueJSON:
+			parsed.json = true
+		case tagValueGob:
+			parsed.gob = true
+		case tagValueChunked:
+			parsed.chunked = true
+		case tagValueOrdered:
+			parsed.ordered = true
 		default:
+			if isRule(part) {
+				parsed.rules = append(parsed.rules, part)
+				continue
+			}
 			if parsed.name == "" {
 				parsed.name = part
 			} else {
-				return tag{}, fmt.Errorf("invalid tag: %s", tagString)
+				return tag{}, fmt.Errorf("%w: %s", ErrInvalidTag, tagString)
 			}
 		}
 	}
@@ -571,5 +1066,19 @@ func parseTag(tagString string) (tag, error) {
 		parsed.name = tagValueMeta
 	}
 
+	if parsed.json && parsed.gob {
+		return tag{}, fmt.Errorf("%w: %s: json conflicts with gob", ErrInvalidTag, tagString)
+	}
+
+	if parsed.ordered && parsed.serialized() {
+		return tag{}, fmt.Errorf("%w: %s: ordered conflicts with serialization", ErrInvalidTag,
+			tagString)
+	}
+
+	if parsed.nullable && parsed.omitempty {
+		return tag{}, fmt.Errorf("%w: %s: nullable conflicts with omitempty", ErrInvalidTag,
+			tagString)
+	}
+
 	return parsed, nil
 }
@@ -3,10 +3,12 @@ package mapper_test
 import (
 	"log"
 	"testing"
+	"time"
 
 	mapper "github.com/reugn/aerospike-mapper-go"
 	"github.com/reugn/aerospike-mapper-go/internal/assert"
 	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+	"github.com/reugn/aerospike-mapper-go/particle"
 )
 
 func TestMapper_Decode(t *testing.T) {
@@ -56,6 +58,48 @@ func TestMapper_Decode(t *testing.T) {
 	}
 }
 
+func TestMapper_DecodeNonStruct(t *testing.T) {
+	record1, err := newTestRecord()
+	assert.IsNil(t, err)
+
+	var digest [20]byte
+	copy(digest[:], record1.Key.Digest())
+
+	t.Run("record", func(t *testing.T) {
+		var record mapper.Record
+		err := mapper.Decode(record1, &record)
+		assert.IsNil(t, err)
+
+		assert.Equal(t, record.Namespace, "ns1")
+		assert.Equal(t, record.SetName, "set1")
+		assert.Equal(t, record.Digest, digest)
+		assert.Equal(t, record.UserKey, any("key1"))
+		assert.Equal(t, record.Generation, 3)
+		assert.Equal(t, record.Expiration, 1_000)
+		assert.Equal(t, record.Bins, map[string]any(record1.Bins))
+	})
+
+	t.Run("map", func(t *testing.T) {
+		var m map[string]any
+		err := mapper.Decode(testtypes.BatchRead{
+			BatchRecord: testtypes.BatchRecord{
+				Record: record1,
+			},
+		}, &m)
+		assert.IsNil(t, err)
+
+		assert.Equal(t, len(m), len(record1.Bins)+6)
+		assert.Equal(t, m[mapper.MapKeyNamespace], any("ns1"))
+		assert.Equal(t, m[mapper.MapKeySetName], any("set1"))
+		assert.Equal(t, m[mapper.MapKeyDigest], any(digest))
+		assert.Equal(t, m[mapper.MapKeyUserKey], any("key1"))
+		assert.Equal(t, m[mapper.MapKeyGeneration], any(uint32(3)))
+		assert.Equal(t, m[mapper.MapKeyExpiration], any(uint32(1_000)))
+		assert.Equal(t, m["title"], any("title1"))
+		assert.Equal(t, m["list"], any([]int{1, 2, 3}))
+	})
+}
+
 func TestMapper_DecodeNegative(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -88,6 +132,68 @@ func TestMapper_DecodeNegative(t *testing.T) {
 	}
 }
 
+func TestMapper_DecodeRecordMetadata(t *testing.T) {
+	key1, err := testtypes.NewKey("ns1", "set1", "key1")
+	assert.IsNil(t, err)
+
+	lastUpdate := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	record := struct {
+		Key        *testtypes.Key
+		Bins       testtypes.BinMap
+		Generation uint32
+		Expiration uint32
+		LastUpdate int64
+		VoidTime   uint32
+		Size       uint32
+	}{
+		Key:        key1,
+		Bins:       testtypes.BinMap{"title": "title1"},
+		Generation: 3,
+		Expiration: 1_000,
+		LastUpdate: lastUpdate.UnixNano(),
+		VoidTime:   454_896_000, // seconds since 2010-01-01 UTC
+		Size:       512,
+	}
+
+	var item testtypes.ItemMeta
+	err = mapper.Decode(record, &item)
+	assert.IsNil(t, err)
+
+	assert.Equal(t, item.Generation, 3)
+	assert.Equal(t, item.Expiration, 1_000)
+	assert.Equal(t, item.LastUpdate.UTC(), lastUpdate)
+	assert.Equal(t, item.VoidTime.UTC(), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, item.Size, 512)
+	assert.Equal(t, item.Title, "title1")
+
+	// the integer fields hold the server value, and the records that never expire
+	// have the zero void time
+	var item2 struct {
+		VoidTime    time.Time `aero:"meta,void_time"`
+		VoidSeconds uint32    `aero:"meta,void_time"`
+	}
+	err = mapper.Decode(record, &item2)
+	assert.IsNil(t, err)
+	assert.Equal(t, item2.VoidSeconds, 454_896_000)
+
+	record.VoidTime = 0
+	err = mapper.Decode(record, &item2)
+	assert.IsNil(t, err)
+	assert.Equal(t, item2.VoidTime.IsZero(), true)
+
+	// the source record does not expose the optional metadata fields
+	record1, err := newTestRecord()
+	assert.IsNil(t, err)
+
+	var item1 testtypes.ItemMeta
+	err = mapper.Decode(record1, &item1)
+	assert.IsNil(t, err)
+
+	assert.Equal(t, item1.Generation, 3)
+	assert.Equal(t, item1.LastUpdate.IsZero(), true)
+	assert.Equal(t, item1.Size, 0)
+}
+
 func TestMapper_Encode(t *testing.T) {
 	record1, err := newTestRecord()
 	assert.IsNil(t, err)
@@ -117,6 +223,216 @@ func TestMapper_Encode(t *testing.T) {
 	assert.Equal(t, item.Dict, map[string]int{"a": 1, "b": 2, "c": 3})
 }
 
+func TestMapper_EncodeInto(t *testing.T) {
+	pool := mapper.NewRecordPool()
+	record := pool.Get()
+	record.Bins["stale"] = 1
+	record.Generation = 10
+
+	err := mapper.EncodeInto(&testtypes.User{Name: "name1"}, record)
+	assert.IsNil(t, err)
+	assert.Equal(t, *record, mapper.Record{
+		Key:  mapper.Key{Namespace: "test", SetName: "users"},
+		Bins: map[string]any{"name": "name1", "login_count": 0},
+	})
+
+	pool.Put(record)
+	record = pool.Get()
+	assert.Equal(t, len(record.Bins), 0)
+
+	err = mapper.EncodeInto(1, record)
+	assert.ErrorIs(t, err, mapper.ErrInvalidSourceType)
+}
+
+func TestMapper_EncodeNullable(t *testing.T) {
+	nickname := "nick"
+	record, err := mapper.Encode(&testtypes.Profile{
+		Name:     "name1",
+		Nickname: &nickname,
+		Bio:      "bio1",
+		Extra:    mapper.DeleteBin,
+	})
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins, map[string]any{
+		"name": "name1", "nickname": "nick", "bio": "bio1", "extra": nil,
+	})
+
+	record, err = mapper.Encode(&testtypes.Profile{})
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins, map[string]any{
+		"name": "", "nickname": nil, "bio": nil, "extra": nil,
+	})
+
+	var profile testtypes.Profile
+	err = mapper.Decode(&testtypes.Record{Bins: testtypes.BinMap(record.Bins)}, &profile)
+	assert.IsNil(t, err)
+	assert.Equal(t, profile, testtypes.Profile{})
+
+	_, err = mapper.Encode(&struct {
+		Name string `aero:"name,nullable,omitempty"`
+	}{})
+	assert.ErrorIs(t, err, mapper.ErrInvalidTag)
+}
+
+func TestMapper_EncodeKeyDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		namespace string
+		setName   string
+	}{
+		{
+			name:      "value",
+			value:     testtypes.User{Name: "name1"},
+			namespace: "test",
+			setName:   "users",
+		},
+		{
+			name:      "pointer",
+			value:     &testtypes.User{Name: "name1"},
+			namespace: "test",
+			setName:   "users",
+		},
+		{
+			name: "explicit",
+			value: &testtypes.User{
+				Key:  mapper.Key{Namespace: "ns1", SetName: "set1"},
+				Name: "name1",
+			},
+			namespace: "ns1",
+			setName:   "set1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := mapper.Encode(test.value)
+			assert.IsNil(t, err)
+			assert.Equal(t, encoded.Namespace, test.namespace)
+			assert.Equal(t, encoded.SetName, test.setName)
+			assert.Equal(t, encoded.Bins["name"], "name1")
+		})
+	}
+}
+
+func TestMapper_DecodeRawBlob(t *testing.T) {
+	record := &testtypes.Record{
+		Bins: testtypes.BinMap{
+			"length": 10,
+			"list":   testtypes.EncodeRawBlobValue([]any{1, 2, 3}),
+			"dict":   testtypes.EncodeRawBlobValue(map[string]int{"a": 1}),
+		},
+	}
+
+	var item testtypes.Item
+	err := mapper.Decode(record, &item)
+	assert.IsNil(t, err)
+	assert.Equal(t, item.IntList, []int{1, 2, 3})
+	assert.Equal(t, item.Dict, map[string]int{"a": 1})
+
+	record.Bins["list"] = testtypes.NewRawBlobValue(int(particle.List), []byte{0x92, 0x01})
+	err = mapper.Decode(record, &item)
+	assert.ErrorIs(t, err, particle.ErrInvalidData)
+}
+
+func TestMapper_DecodeEncodedRecord(t *testing.T) {
+	ptr := 7
+	digest := [20]byte{1, 2, 3}
+	tests := []struct {
+		name  string
+		value any
+		empty any
+	}{
+		{
+			name: "allKinds",
+			value: &testtypes.AllKinds{
+				Key: mapper.Key{
+					Namespace: "ns1",
+					SetName:   "set1",
+					Digest:    digest,
+				},
+				KeyValue: mapper.KeyValue{UserKey: "key1"},
+				Metadata: mapper.Metadata{Generation: 2, Expiration: 100},
+				Nested:   testtypes.Nested{City: "city1", Zip: 12345},
+				String:   "string1",
+				Int:      -1,
+				Int8:     -8,
+				Int16:    -16,
+				Int32:    -32,
+				Int64:    -64,
+				Uint:     1,
+				Uint8:    8,
+				Uint16:   16,
+				Uint32:   32,
+				Uint64:   64,
+				Float32:  3.2,
+				Float64:  6.4,
+				Bool:     true,
+				Bytes:    []byte{1, 2, 3},
+				Strings:  []string{"a", "b"},
+				Map:      map[string]int{"a": 1},
+				Nested2:  map[string][]bool{"a": {true, false}},
+				Ptr:      &ptr,
+				Any:      map[any]any{"a": 1},
+				Time:     time.Unix(100, 0),
+			},
+			empty: &testtypes.AllKinds{},
+		},
+		{
+			name: "item",
+			value: &testtypes.Item{
+				Key:         mapper.Key{Namespace: "ns1", SetName: "set1"},
+				KeyValue:    mapper.KeyValue{UserKey: 10},
+				Item1:       testtypes.Item1{Title: "title1"},
+				Length:      10,
+				Description: "description1",
+				IntList:     []int{1, 2, 3},
+				Dict:        map[string]int{"a": 1},
+			},
+			empty: &testtypes.Item{},
+		},
+		{
+			name: "user",
+			value: &testtypes.User{
+				Key:        mapper.Key{Namespace: "test", SetName: "users"},
+				Name:       "name1",
+				LoginCount: 3,
+			},
+			empty: &testtypes.User{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := mapper.Encode(test.value)
+			assert.IsNil(t, err)
+
+			// decode from both the record and the pointer to it
+			for _, record := range []any{encoded, *encoded} {
+				err = mapper.Decode(record, test.empty)
+				assert.IsNil(t, err)
+				assert.Equal(t, test.empty, test.value)
+			}
+		})
+	}
+}
+
+func TestMapper_DecodeEncodedRecordDigest(t *testing.T) {
+	digest := [20]byte{1, 2, 3}
+	record := &mapper.Record{
+		Key: mapper.Key{Namespace: "ns1", Digest: digest},
+	}
+
+	var target struct {
+		Namespace string `aero:"meta,namespace"`
+		Digest    []byte `aero:"meta,digest"`
+	}
+	err := mapper.Decode(record, &target)
+	assert.IsNil(t, err)
+	assert.Equal(t, target.Namespace, "ns1")
+	assert.Equal(t, target.Digest, digest[:])
+}
+
 func newTestRecord() (*testtypes.Record, error) {
 	key1, err := testtypes.NewKey("ns1", "set1", "key1")
 	if err != nil {
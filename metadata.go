@@ -1,5 +1,17 @@
 package mapper
 
+import "math"
+
+// The special Expiration values, following the Aerospike TTL semantics.
+const (
+	// ExpirationNamespaceDefault sets the record TTL to the default TTL of the namespace.
+	ExpirationNamespaceDefault uint32 = 0
+	// ExpirationNever sets the record to never expire.
+	ExpirationNever uint32 = math.MaxUint32
+	// ExpirationDontUpdate preserves the existing record TTL on update.
+	ExpirationDontUpdate uint32 = math.MaxUint32 - 1
+)
+
 // Metadata holds the Aerospike record generation and expiration details.
 // Embed this struct into your data model to automatically unpack metadata values
 // when reading records from Aerospike, or to specify metadata values when encoding.
@@ -0,0 +1,9 @@
+package ops
+
+import "errors"
+
+var (
+	ErrFieldRequired    = errors.New("bin operation requires a field")
+	ErrInvalidOperation = errors.New("operation is not applicable to the field")
+	ErrTransformedField = errors.New("operation is not applicable to a transformed field")
+)
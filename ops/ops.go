@@ -0,0 +1,199 @@
+// Package ops builds client-independent record operation descriptors, where the
+// bin operations are addressed by struct field and resolved to bin names using
+// the mapper `aero` tags.
+package ops
+
+import (
+	"fmt"
+	"reflect"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+)
+
+// Type is the type of the record operation.
+type Type int
+
+const (
+	// TypePut writes the value to the bin.
+	TypePut Type = iota
+	// TypeAdd adds the numeric value to the bin.
+	TypeAdd
+	// TypeAppend appends the string value to the bin.
+	TypeAppend
+	// TypePrepend prepends the string value to the bin.
+	TypePrepend
+	// TypeTouch resets the record time-to-live.
+	TypeTouch
+	// TypeDelete deletes the record.
+	TypeDelete
+	// TypeListAppend appends the values to the list bin.
+	TypeListAppend
+	// TypeMapPut puts the key/value pair to the map bin.
+	TypeMapPut
+)
+
+var typeNames = [...]string{
+	TypePut:        "put",
+	TypeAdd:        "add",
+	TypeAppend:     "append",
+	TypePrepend:    "prepend",
+	TypeTouch:      "touch",
+	TypeDelete:     "delete",
+	TypeListAppend: "list_append",
+	TypeMapPut:     "map_put",
+}
+
+// String returns the name of the operation type.
+func (t Type) String() string {
+	if t < 0 || int(t) >= len(typeNames) {
+		return fmt.Sprintf("Type(%d)", int(t))
+	}
+	return typeNames[t]
+}
+
+// Operation describes a single record operation.
+type Operation struct {
+	// Type is the type of the operation.
+	Type Type
+	// BinName is the name of the bin the operation applies to.
+	// It is empty for the record level operations.
+	BinName string
+	// MapKey is the map key of the TypeMapPut operation.
+	MapKey any
+	// Value is the operation value. For TypeListAppend it holds a []any of
+	// the appended values.
+	Value any
+
+	// field is the pointer to the struct field the operation applies to,
+	// resolved to the BinName by Build.
+	field any
+}
+
+// Put returns an operation that writes the value to the bin of the field.
+func Put(field any, value any) Operation {
+	return Operation{Type: TypePut, Value: value, field: field}
+}
+
+// Add returns an operation that adds the delta to the numeric bin of the field.
+func Add(field any, delta any) Operation {
+	return Operation{Type: TypeAdd, Value: delta, field: field}
+}
+
+// Append returns an operation that appends the value to the string bin of the field.
+func Append(field any, value string) Operation {
+	return Operation{Type: TypeAppend, Value: value, field: field}
+}
+
+// Prepend returns an operation that prepends the value to the string bin of the field.
+func Prepend(field any, value string) Operation {
+	return Operation{Type: TypePrepend, Value: value, field: field}
+}
+
+// ListAppend returns an operation that appends the values to the list bin of the field.
+func ListAppend(field any, values ...any) Operation {
+	return Operation{Type: TypeListAppend, Value: values, field: field}
+}
+
+// MapPut returns an operation that puts the key/value pair to the map bin of the field.
+func MapPut(field any, key any, value any) Operation {
+	return Operation{Type: TypeMapPut, MapKey: key, Value: value, field: field}
+}
+
+// Touch returns an operation that resets the record time-to-live.
+func Touch() Operation {
+	return Operation{Type: TypeTouch}
+}
+
+// Delete returns an operation that deletes the record.
+func Delete() Operation {
+	return Operation{Type: TypeDelete}
+}
+
+// Build resolves the bin names of the operations using the `aero` tags of v,
+// and validates the operations against the field types. The operations on the
+// fields transformed on encode (e.g. encrypted or compressed) are rejected with
+// ErrTransformedField, as the operation values would be written untransformed.
+// v must be a pointer to the struct whose fields the operations are addressed by.
+//
+// Example:
+//
+//	operations, err := ops.Build(&user,
+//		ops.Add(&user.LoginCount, 1),
+//		ops.Append(&user.Name, "!"),
+//		ops.Touch(),
+//	)
+func Build(v any, operations ...Operation) ([]Operation, error) {
+	result := make([]Operation, len(operations))
+	for i, operation := range operations {
+		if operation.field != nil {
+			binName, err := mapper.BinName(v, operation.field)
+			if err != nil {
+				return nil, fmt.Errorf("%s operation %d: %w", operation.Type, i, err)
+			}
+			transformed, err := mapper.IsTransformed(v, operation.field)
+			if err != nil {
+				return nil, fmt.Errorf("%s operation %d: %w", operation.Type, i, err)
+			}
+			if transformed {
+				return nil, fmt.Errorf("%s operation %d on bin %q: %w",
+					operation.Type, i, binName, ErrTransformedField)
+			}
+			if err := validate(operation); err != nil {
+				return nil, fmt.Errorf("%s operation %d on bin %q: %w",
+					operation.Type, i, binName, err)
+			}
+			operation.BinName = binName
+			operation.field = nil
+		} else if requiresField(operation.Type) {
+			return nil, fmt.Errorf("%s operation %d: %w", operation.Type, i, ErrFieldRequired)
+		}
+		result[i] = operation
+	}
+
+	return result, nil
+}
+
+// requiresField reports whether the operation type applies to a bin.
+func requiresField(t Type) bool {
+	return t != TypeTouch && t != TypeDelete
+}
+
+// validate checks that the operation is applicable to the type of its field.
+func validate(operation Operation) error {
+	fieldType := reflect.TypeOf(operation.field).Elem()
+	switch operation.Type {
+	case TypeAdd:
+		if !isNumeric(fieldType.Kind()) {
+			return fmt.Errorf("%w: %s field", ErrInvalidOperation, fieldType)
+		}
+		if operation.Value == nil || !isNumeric(reflect.TypeOf(operation.Value).Kind()) {
+			return fmt.Errorf("%w: non-numeric delta %T", ErrInvalidOperation, operation.Value)
+		}
+	case TypeAppend, TypePrepend:
+		if fieldType.Kind() != reflect.String {
+			return fmt.Errorf("%w: %s field", ErrInvalidOperation, fieldType)
+		}
+	case TypeListAppend:
+		if fieldType.Kind() != reflect.Slice && fieldType.Kind() != reflect.Array {
+			return fmt.Errorf("%w: %s field", ErrInvalidOperation, fieldType)
+		}
+	case TypeMapPut:
+		if fieldType.Kind() != reflect.Map {
+			return fmt.Errorf("%w: %s field", ErrInvalidOperation, fieldType)
+		}
+	}
+
+	return nil
+}
+
+// isNumeric reports whether the kind is an integer or a floating-point number.
+func isNumeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,92 @@
+package ops_test
+
+import (
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+	"github.com/reugn/aerospike-mapper-go/ops"
+)
+
+func TestOps_Build(t *testing.T) {
+	item := testtypes.AllKinds{}
+	operations, err := ops.Build(&item,
+		ops.Put(&item.String, "value"),
+		ops.Add(&item.Int, 1),
+		ops.Append(&item.String, "suffix"),
+		ops.Prepend(&item.String, "prefix"),
+		ops.ListAppend(&item.Strings, "a", "b"),
+		ops.MapPut(&item.Map, "key", 3),
+		ops.Add(&item.Zip, 10),
+		ops.Touch(),
+		ops.Delete(),
+	)
+	assert.IsNil(t, err)
+
+	expected := []ops.Operation{
+		{Type: ops.TypePut, BinName: "string", Value: "value"},
+		{Type: ops.TypeAdd, BinName: "int", Value: 1},
+		{Type: ops.TypeAppend, BinName: "string", Value: "suffix"},
+		{Type: ops.TypePrepend, BinName: "string", Value: "prefix"},
+		{Type: ops.TypeListAppend, BinName: "strings", Value: []any{"a", "b"}},
+		{Type: ops.TypeMapPut, BinName: "map", MapKey: "key", Value: 3},
+		{Type: ops.TypeAdd, BinName: "zip", Value: 10},
+		{Type: ops.TypeTouch},
+		{Type: ops.TypeDelete},
+	}
+	assert.Equal(t, operations, expected)
+}
+
+func TestOps_BuildErrors(t *testing.T) {
+	item := testtypes.AllKinds{}
+	other := ""
+
+	tests := []struct {
+		name      string
+		operation ops.Operation
+		err       error
+	}{
+		{"addString", ops.Add(&item.String, 1), ops.ErrInvalidOperation},
+		{"addNonNumeric", ops.Add(&item.Int, "1"), ops.ErrInvalidOperation},
+		{"addNil", ops.Add(&item.Int, nil), ops.ErrInvalidOperation},
+		{"appendInt", ops.Append(&item.Int, "1"), ops.ErrInvalidOperation},
+		{"prependInt", ops.Prepend(&item.Int, "1"), ops.ErrInvalidOperation},
+		{"listAppendMap", ops.ListAppend(&item.Map, 1), ops.ErrInvalidOperation},
+		{"mapPutSlice", ops.MapPut(&item.Strings, "key", 1), ops.ErrInvalidOperation},
+		{"meta", ops.Put(&item.Generation, 1), mapper.ErrFieldNotMapped},
+		{"notFound", ops.Put(&other, "value"), mapper.ErrFieldNotMapped},
+		{"noField", ops.Put(nil, "value"), ops.ErrFieldRequired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			operations, err := ops.Build(&item, ops.Touch(), test.operation)
+			assert.ErrorIs(t, err, test.err)
+			assert.IsNil(t, operations)
+		})
+	}
+}
+
+func TestOps_TypeString(t *testing.T) {
+	assert.Equal(t, ops.TypeAdd.String(), "add")
+	assert.Equal(t, ops.TypeMapPut.String(), "map_put")
+	assert.Equal(t, ops.Type(100).String(), "Type(100)")
+}
+
+func TestOps_BuildTransformed(t *testing.T) {
+	patient := testtypes.Patient{}
+	_, err := ops.Build(&patient, ops.Put(&patient.SSN, "123-45-6789"))
+	assert.ErrorIs(t, err, ops.ErrTransformedField)
+
+	document := testtypes.Document{}
+	_, err = ops.Build(&document, ops.Append(&document.Title, "suffix"))
+	assert.ErrorIs(t, err, ops.ErrTransformedField)
+
+	leaderboard := testtypes.Leaderboard{}
+	_, err = ops.Build(&leaderboard, ops.MapPut(&leaderboard.Scores, "a", 1))
+	assert.ErrorIs(t, err, ops.ErrTransformedField)
+
+	_, err = ops.Build(&patient, ops.Put(&patient.Name, "name1"))
+	assert.IsNil(t, err)
+}
@@ -0,0 +1,84 @@
+package mapper
+
+import (
+	"compress/flate"
+	"compress/gzip"
+)
+
+// Mapper encodes and decodes records using the configured options.
+// The package level Encode, EncodeInto and Decode functions use a Mapper
+// with the default configuration.
+type Mapper struct {
+	// cipher encrypts and decrypts the values of the `encrypt` fields.
+	cipher Cipher
+	// codec compresses the values of the `compress` fields.
+	codec Codec
+	// codecs contains the codecs used to decompress the values by the codec ID.
+	codecs map[byte]Codec
+	// compressionThreshold is the minimum size in bytes of the values to compress.
+	compressionThreshold int
+	// maxRecordSize is the maximum estimated size of the encoded records.
+	maxRecordSize int
+}
+
+// Option configures the Mapper.
+type Option func(*Mapper)
+
+// WithCipher sets the Cipher used to encrypt and decrypt the values of the fields
+// tagged with the `encrypt` option.
+func WithCipher(cipher Cipher) Option {
+	return func(m *Mapper) {
+		m.cipher = cipher
+	}
+}
+
+// WithCodec sets the Codec used to compress the values of the fields tagged with
+// the `compress` option. The values compressed by the built-in codecs can still be
+// decompressed. It panics if the codec is nil, or if a custom codec uses an ID
+// reserved for the built-in codecs.
+func WithCodec(codec Codec) Option {
+	return func(m *Mapper) {
+		if err := checkCodec(codec); err != nil {
+			panic("mapper: " + err.Error())
+		}
+		m.codec = codec
+		m.codecs[codec.ID()] = codec
+	}
+}
+
+// WithCompressionThreshold sets the minimum size in bytes of the values to compress.
+// Smaller values are stored uncompressed. Defaults to DefaultCompressionThreshold.
+func WithCompressionThreshold(threshold int) Option {
+	return func(m *Mapper) {
+		m.compressionThreshold = threshold
+	}
+}
+
+// WithMaxRecordSize sets the maximum estimated size in bytes of the encoded records,
+// typically the write-block size of the namespace. Encoding a larger record fails with
+// a *RecordTooLargeError. See EstimateSize for details. Zero means no limit.
+func WithMaxRecordSize(size int) Option {
+	return func(m *Mapper) {
+		m.maxRecordSize = size
+	}
+}
+
+// defaultMapper is the Mapper used by the package level functions.
+var defaultMapper = New()
+
+// New returns a new Mapper configured with the given options.
+func New(opts ...Option) *Mapper {
+	gzipCodec := NewGzipCodec(gzip.DefaultCompression)
+	m := &Mapper{
+		codec: gzipCodec,
+		codecs: map[byte]Codec{
+			CodecGzip:  gzipCodec,
+			CodecFlate: NewFlateCodec(flate.DefaultCompression),
+		},
+		compressionThreshold: DefaultCompressionThreshold,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
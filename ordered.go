@@ -0,0 +1,364 @@
+package mapper
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/reugn/aerospike-mapper-go/particle"
+)
+
+// MapPair is a key-value entry of an ordered map. A []MapPair bin value is written
+// as a key-ordered Aerospike map, and the clients return ordered maps in this form.
+type MapPair = particle.MapPair
+
+// OrderedMapEntry is a key-value entry of an OrderedMap.
+type OrderedMapEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// OrderedMap is a map that preserves the order of its entries. It is encoded as
+// a key-ordered Aerospike map, with the entries sorted by key, and can be decoded
+// from both ordered and unordered maps, in which case the entries are sorted by key.
+type OrderedMap[K comparable, V any] []OrderedMapEntry[K, V]
+
+// Get returns the value for the given key, and reports whether the key was found.
+func (om OrderedMap[K, V]) Get(key K) (V, bool) {
+	for _, entry := range om {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// MapPairs returns the entries of the map as a MapPair slice.
+func (om OrderedMap[K, V]) MapPairs() []MapPair {
+	if om == nil {
+		return nil
+	}
+	pairs := make([]MapPair, len(om))
+	for i, entry := range om {
+		pairs[i] = MapPair{Key: entry.Key, Value: entry.Value}
+	}
+	return pairs
+}
+
+// mapPairsType is the reflect type of the MapPair slice.
+var mapPairsType = reflect.TypeOf([]MapPair(nil))
+
+// encodeOrdered returns the MapPair slice sorted by key for the OrderedMap values and
+// the values of the fields tagged with `ordered`. Other values are returned unchanged.
+func encodeOrdered(t *tag, value any) (any, error) {
+	if om, ok := value.(interface{ MapPairs() []MapPair }); ok {
+		pairs := om.MapPairs()
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return lessKey(reflect.ValueOf(pairs[i].Key), reflect.ValueOf(pairs[j].Key))
+		})
+		return pairs, nil
+	}
+	if !t.ordered {
+		return value, nil
+	}
+
+	sourceValue := reflect.ValueOf(value)
+	if sourceValue.Kind() != reflect.Map {
+		return nil, fmt.Errorf("ordered: unsupported type %T", value)
+	}
+
+	keys, values := mapEntries(sourceValue)
+	pairs := make([]MapPair, len(keys))
+	for i := range keys {
+		pairs[i] = MapPair{Key: keys[i].Interface(), Value: values[i].Interface()}
+	}
+	return pairs, nil
+}
+
+// isMapPairSlice reports whether t is a slice of structs with the Key and Value fields,
+// like MapPair, OrderedMap and the client MapPair slices.
+func isMapPairSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Struct ||
+		t.Elem().NumField() != 2 {
+		return false
+	}
+	_, hasKey := t.Elem().FieldByName("Key")
+	_, hasValue := t.Elem().FieldByName("Value")
+	return hasKey && hasValue
+}
+
+// mapEntries returns the keys and values of the map or the MapPair slice v.
+// The map entries are sorted by key, while the slice entries keep their order.
+func mapEntries(v reflect.Value) ([]reflect.Value, []reflect.Value) {
+	if v.Kind() == reflect.Slice {
+		keys := make([]reflect.Value, v.Len())
+		values := make([]reflect.Value, v.Len())
+		for i := range keys {
+			keys[i] = v.Index(i).FieldByName("Key")
+			values[i] = v.Index(i).FieldByName("Value")
+		}
+		return keys, values
+	}
+
+	keys := v.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+	values := make([]reflect.Value, len(keys))
+	for i, key := range keys {
+		values[i] = v.MapIndex(key)
+	}
+	return keys, values
+}
+
+// lessKey reports whether the map key a sorts before b in the Aerospike key order.
+func lessKey(a, b reflect.Value) bool {
+	return compareKeys(a, b) < 0
+}
+
+// compareKeys compares the map keys a and b in the Aerospike key order, which ranks the
+// types first: nil, bool, integer, string, list, map, bytes, float and GeoJSON. The values
+// of the same type are compared by value, the lists element by element, and the bytes
+// lexicographically. The maps and the unsupported types are compared by their string
+// representation.
+func compareKeys(a, b reflect.Value) int {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+
+	aRank, bRank := keyRank(a), keyRank(b)
+	if aRank != bRank {
+		return aRank - bRank
+	}
+
+	switch aRank {
+	case keyRankNil:
+		return 0
+	case keyRankBool:
+		return compareBools(a.Bool(), b.Bool())
+	case keyRankInteger:
+		return compareIntegers(a, b)
+	case keyRankString, keyRankGeoJSON:
+		return strings.Compare(a.String(), b.String())
+	case keyRankList:
+		for i := 0; i < a.Len() && i < b.Len(); i++ {
+			if c := compareKeys(a.Index(i), b.Index(i)); c != 0 {
+				return c
+			}
+		}
+		return a.Len() - b.Len()
+	case keyRankBytes:
+		return bytes.Compare(keyBytes(a), keyBytes(b))
+	case keyRankFloat:
+		return compareOrdered(a.Float(), b.Float())
+	default:
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	}
+}
+
+// The ranks of the map key types in the Aerospike key order.
+const (
+	keyRankNil = iota
+	keyRankBool
+	keyRankInteger
+	keyRankString
+	keyRankList
+	keyRankMap
+	keyRankBytes
+	keyRankFloat
+	keyRankGeoJSON
+	keyRankOther
+)
+
+// geoJSONTypes contains the types of the GeoJSON values.
+var geoJSONTypes = map[reflect.Type]struct{}{
+	reflect.TypeOf(GeoJSON("")):               {},
+	reflect.TypeOf(particle.GeoJSONValue("")): {},
+}
+
+// keyRank returns the rank of the map key type in the Aerospike key order.
+func keyRank(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return keyRankNil
+	case reflect.Bool:
+		return keyRankBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return keyRankInteger
+	case reflect.String:
+		if _, ok := geoJSONTypes[v.Type()]; ok {
+			return keyRankGeoJSON
+		}
+		return keyRankString
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return keyRankBytes
+		}
+		if v.Type() == mapPairsType {
+			return keyRankMap
+		}
+		return keyRankList
+	case reflect.Map:
+		return keyRankMap
+	case reflect.Float32, reflect.Float64:
+		return keyRankFloat
+	default:
+		return keyRankOther
+	}
+}
+
+// compareBools compares the booleans, where false sorts before true.
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareIntegers compares the signed or unsigned integer values.
+func compareIntegers(a, b reflect.Value) int {
+	switch {
+	case a.CanInt() && b.CanInt():
+		return compareOrdered(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return compareOrdered(a.Uint(), b.Uint())
+	case a.CanInt(): // b is unsigned
+		if a.Int() < 0 {
+			return -1
+		}
+		return compareOrdered(uint64(a.Int()), b.Uint())
+	default: // a is unsigned
+		if b.Int() < 0 {
+			return 1
+		}
+		return compareOrdered(a.Uint(), uint64(b.Int()))
+	}
+}
+
+// compareOrdered compares the values of an ordered type.
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// keyBytes returns the bytes of the byte slice or array value.
+func keyBytes(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+	b := make([]byte, v.Len())
+	for i := range b {
+		b[i] = byte(v.Index(i).Uint())
+	}
+	return b
+}
+
+// convertToMap converts the MapPair slice source to a map of the target type.
+func convertToMap(sourceValue reflect.Value, targetType reflect.Type) (reflect.Value, error) {
+	keys, values := mapEntries(sourceValue)
+	newMap := reflect.MakeMapWithSize(targetType, len(keys))
+	for i := range keys {
+		convertedKey, err := convertElementType(keys[i].Interface(), targetType.Key())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error converting map key: %w", err)
+		}
+
+		convertedValue, err := convertElementType(values[i].Interface(), targetType.Elem())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error converting map value: %w", err)
+		}
+
+		newMap.SetMapIndex(convertedKey, convertedValue)
+	}
+	return newMap, nil
+}
+
+// convertToPairs converts the map source to a slice of key-value structs of the
+// target type, sorted by key.
+func convertToPairs(sourceValue reflect.Value, targetType reflect.Type) (reflect.Value, error) {
+	keys, values := mapEntries(sourceValue)
+	newSlice := reflect.MakeSlice(targetType, len(keys), len(keys))
+	for i := range keys {
+		entry := newSlice.Index(i)
+
+		keyField := entry.FieldByName("Key")
+		convertedKey, err := convertElementType(keys[i].Interface(), keyField.Type())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error converting map key: %w", err)
+		}
+		keyField.Set(convertedKey)
+
+		valueField := entry.FieldByName("Value")
+		convertedValue, err := convertElementType(values[i].Interface(), valueField.Type())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error converting map value: %w", err)
+		}
+		valueField.Set(convertedValue)
+	}
+	return newSlice, nil
+}
+
+// convertMapToStruct converts the map or MapPair slice source to a struct of the
+// target type. The keys are matched against the `aero` tag names of the fields,
+// falling back to the field names for untagged fields.
+func convertMapToStruct(sourceValue reflect.Value, targetType reflect.Type) (reflect.Value, error) {
+	fields, err := cachedFields(targetType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	fieldIndex := make(map[string]int, len(fields))
+	for i := range fields {
+		tag := fields[i].tag
+		switch {
+		case !targetType.Field(i).IsExported(), tag.meta, tag.omit:
+		case fields[i].tagged && tag.name != "":
+			fieldIndex[tag.name] = i
+		case !fields[i].tagged:
+			fieldIndex[targetType.Field(i).Name] = i
+		}
+	}
+
+	newStruct := reflect.New(targetType).Elem()
+	keys, values := mapEntries(sourceValue)
+	for i, key := range keys {
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() != reflect.String {
+			continue // only the string keys can be mapped to the fields
+		}
+
+		index, ok := fieldIndex[key.String()]
+		if !ok {
+			continue
+		}
+
+		fieldValue := newStruct.Field(index)
+		convertedValue, err := convertElementType(values[i].Interface(), fieldValue.Type())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error converting field %s: %w",
+				targetType.Field(index).Name, err)
+		}
+		fieldValue.Set(convertedValue)
+	}
+
+	return newStruct, nil
+}
@@ -0,0 +1,116 @@
+package mapper_test
+
+import (
+	"strconv"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+	"github.com/reugn/aerospike-mapper-go/particle"
+)
+
+func TestMapper_EncodeOrdered(t *testing.T) {
+	leaderboard := testtypes.Leaderboard{
+		Scores: map[string]int{"c": 3, "a": 1, "b": 2},
+		Ranks:  mapper.OrderedMap[int, string]{{Key: 2, Value: "b"}, {Key: 1, Value: "a"}},
+	}
+
+	record, err := mapper.Encode(&leaderboard)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["scores"], any([]mapper.MapPair{
+		{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3},
+	}))
+	assert.Equal(t, record.Bins["ranks"], any([]mapper.MapPair{
+		{Key: 1, Value: "a"}, {Key: 2, Value: "b"},
+	}))
+	assert.Equal(t, record.Bins["players"], any(map[string]testtypes.Score(nil)))
+	assert.Equal(t, record.Bins["levels"], any([]mapper.MapPair(nil)))
+
+	// the ordered maps survive the particle encoding
+	particleType, data, err := particle.Encode(record.Bins["scores"])
+	assert.IsNil(t, err)
+	decoded, err := particle.Decode(particleType, data)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, any([]particle.MapPair{
+		{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: 3},
+	}))
+
+	record, err = mapper.Encode(&testtypes.Leaderboard{})
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["scores"], any([]mapper.MapPair{}))
+
+	_, err = mapper.Encode(&struct {
+		Scores []int `aero:"scores,ordered"`
+	}{})
+	assert.ErrorIs(t, err, mapper.ErrInvalidTag)
+
+	_, err = mapper.Encode(&struct {
+		Scores map[string]int `aero:"scores,ordered,json"`
+	}{})
+	assert.ErrorIs(t, err, mapper.ErrInvalidTag)
+}
+
+func TestMapper_EncodeOrderedKeyOrder(t *testing.T) {
+	record, err := mapper.Encode(&struct {
+		Mixed map[any]int `aero:"mixed,ordered"`
+	}{
+		Mixed: map[any]int{
+			1.5: 1, "a": 2, 10: 3, true: 4, [2]byte{1, 2}: 5, [1]byte{3}: 6,
+			-1: 7, uint(2): 8, "": 9, false: 10, [2]int{1, 2}: 11,
+		},
+	})
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["mixed"], any([]mapper.MapPair{
+		{Key: false, Value: 10}, {Key: true, Value: 4},
+		{Key: -1, Value: 7}, {Key: uint(2), Value: 8}, {Key: 10, Value: 3},
+		{Key: "", Value: 9}, {Key: "a", Value: 2},
+		{Key: [2]int{1, 2}, Value: 11},
+		{Key: [2]byte{1, 2}, Value: 5}, {Key: [1]byte{3}, Value: 6},
+		{Key: 1.5, Value: 1},
+	}))
+}
+
+func TestMapper_DecodeOrdered(t *testing.T) {
+	record := &testtypes.Record{
+		Bins: testtypes.BinMap{
+			"scores": []testtypes.MapPair{{Key: "a", Value: 1}, {Key: "b", Value: 2}},
+			"ranks":  map[any]any{3: "c", 1: "a", 2: "b"},
+			"players": map[any]any{
+				"alice": map[any]any{"points": 10, "grade": "A", "Note": "note1", "other": 1},
+			},
+			"levels": []testtypes.MapPair{
+				{Key: "two", Value: []testtypes.MapPair{{Key: "points", Value: 2}}},
+				{Key: "one", Value: map[any]any{"points": 1, "grade": "B"}},
+			},
+		},
+	}
+
+	var leaderboard testtypes.Leaderboard
+	err := mapper.Decode(record, &leaderboard)
+	assert.IsNil(t, err)
+	assert.Equal(t, leaderboard, testtypes.Leaderboard{
+		Scores: map[string]int{"a": 1, "b": 2},
+		Ranks: mapper.OrderedMap[int, string]{
+			{Key: 1, Value: "a"}, {Key: 2, Value: "b"}, {Key: 3, Value: "c"},
+		},
+		Players: map[string]testtypes.Score{
+			"alice": {Points: 10, Grade: "A", Note: "note1"},
+		},
+		Levels: mapper.OrderedMap[string, testtypes.Score]{
+			{Key: "two", Value: testtypes.Score{Points: 2}},
+			{Key: "one", Value: testtypes.Score{Points: 1, Grade: "B"}},
+		},
+	})
+
+	value, ok := leaderboard.Ranks.Get(2)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, value, "b")
+
+	_, ok = leaderboard.Ranks.Get(4)
+	assert.Equal(t, ok, false)
+
+	record.Bins["scores"] = []testtypes.MapPair{{Key: "a", Value: "x"}}
+	err = mapper.Decode(record, &leaderboard)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
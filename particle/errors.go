@@ -0,0 +1,8 @@
+package particle
+
+import "errors"
+
+var (
+	ErrUnsupportedType = errors.New("unsupported particle value type")
+	ErrInvalidData     = errors.New("invalid particle data")
+)
@@ -0,0 +1,558 @@
+package particle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// The msgpack format type bytes.
+const (
+	mpNil     = 0xc0
+	mpFalse   = 0xc2
+	mpTrue    = 0xc3
+	mpBin8    = 0xc4
+	mpBin16   = 0xc5
+	mpBin32   = 0xc6
+	mpExt8    = 0xc7
+	mpExt16   = 0xc8
+	mpExt32   = 0xc9
+	mpFloat32 = 0xca
+	mpFloat64 = 0xcb
+	mpUint8   = 0xcc
+	mpUint16  = 0xcd
+	mpUint32  = 0xce
+	mpUint64  = 0xcf
+	mpInt8    = 0xd0
+	mpInt16   = 0xd1
+	mpInt32   = 0xd2
+	mpInt64   = 0xd3
+	mpFixExt1 = 0xd4
+	mpFixExt2 = 0xd5
+	mpFixExt4 = 0xd6
+	mpFixExt8 = 0xd7
+	mpFixExt  = 0xd8
+	mpStr8    = 0xd9
+	mpStr16   = 0xda
+	mpStr32   = 0xdb
+	mpArray16 = 0xdc
+	mpArray32 = 0xdd
+	mpMap16   = 0xde
+	mpMap32   = 0xdf
+)
+
+// mapKeyOrdered is the ordered map flag of the map extension header.
+const mapKeyOrdered = 1
+
+// packer encodes the values into the Aerospike msgpack format, where the strings
+// and blobs are prefixed with their particle type.
+type packer struct {
+	buf []byte
+}
+
+// pack encodes the value into the Aerospike msgpack format.
+func pack(v reflect.Value) ([]byte, error) {
+	p := &packer{}
+	if err := p.pack(v); err != nil {
+		return nil, err
+	}
+	return p.buf, nil
+}
+
+func (p *packer) pack(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Invalid:
+		p.buf = append(p.buf, mpNil)
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			p.buf = append(p.buf, mpNil)
+			return nil
+		}
+		return p.pack(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			p.buf = append(p.buf, mpTrue)
+		} else {
+			p.buf = append(p.buf, mpFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p.packInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			p.buf = append(p.buf, mpUint64)
+			p.buf = appendUint64(p.buf, v.Uint())
+			return nil
+		}
+		p.packInt(int64(v.Uint()))
+	case reflect.Float32:
+		p.buf = append(p.buf, mpFloat32)
+		p.buf = appendUint32(p.buf, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		p.buf = append(p.buf, mpFloat64)
+		p.buf = appendUint64(p.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		if v.Type() == geoJSONType {
+			p.packString(GeoJSON, v.String())
+		} else {
+			p.packString(String, v.String())
+		}
+	case reflect.Slice, reflect.Array:
+		switch {
+		case v.Type() == hllType:
+			p.packString(HLL, string(v.Bytes()))
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			p.packString(Blob, string(v.Bytes()))
+		case v.Type() == mapPairsType:
+			return p.packMapPairs(v.Interface().([]MapPair))
+		default:
+			p.packHeader(v.Len(), 0x90, 16, mpArray16, mpArray32)
+			for i := 0; i < v.Len(); i++ {
+				if err := p.pack(v.Index(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Map:
+		p.packHeader(v.Len(), 0x80, 16, mpMap16, mpMap32)
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := p.pack(iter.Key()); err != nil {
+				return err
+			}
+			if err := p.pack(iter.Value()); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type())
+	}
+
+	return nil
+}
+
+// packInt encodes the integer using the smallest msgpack integer format.
+func (p *packer) packInt(i int64) {
+	switch {
+	case i >= 0 && i < 1<<7, i < 0 && i >= -32:
+		p.buf = append(p.buf, byte(i))
+	case i >= 0 && i <= math.MaxUint8:
+		p.buf = append(p.buf, mpUint8, byte(i))
+	case i >= 0 && i <= math.MaxUint16:
+		p.buf = append(p.buf, mpUint16)
+		p.buf = appendUint16(p.buf, uint16(i))
+	case i >= 0 && i <= math.MaxUint32:
+		p.buf = append(p.buf, mpUint32)
+		p.buf = appendUint32(p.buf, uint32(i))
+	case i >= 0:
+		p.buf = append(p.buf, mpUint64)
+		p.buf = appendUint64(p.buf, uint64(i))
+	case i >= math.MinInt8:
+		p.buf = append(p.buf, mpInt8, byte(i))
+	case i >= math.MinInt16:
+		p.buf = append(p.buf, mpInt16)
+		p.buf = appendUint16(p.buf, uint16(i))
+	case i >= math.MinInt32:
+		p.buf = append(p.buf, mpInt32)
+		p.buf = appendUint32(p.buf, uint32(i))
+	default:
+		p.buf = append(p.buf, mpInt64)
+		p.buf = appendUint64(p.buf, uint64(i))
+	}
+}
+
+// packString encodes the string prefixed with the particle type.
+func (p *packer) packString(particleType Type, s string) {
+	p.packHeader(len(s)+1, 0xa0, 32, mpStr16, mpStr32)
+	p.buf = append(p.buf, byte(particleType))
+	p.buf = append(p.buf, s...)
+}
+
+// packMapPairs encodes the pairs as a key ordered map, which is marked by the
+// extension header as the first key, with a nil value.
+func (p *packer) packMapPairs(pairs []MapPair) error {
+	p.packHeader(len(pairs)+1, 0x80, 16, mpMap16, mpMap32)
+	p.buf = append(p.buf, mpExt8, 0, mapKeyOrdered, mpNil)
+	for _, pair := range pairs {
+		if err := p.pack(reflect.ValueOf(pair.Key)); err != nil {
+			return err
+		}
+		if err := p.pack(reflect.ValueOf(pair.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packHeader encodes the length header of a string, array or map.
+func (p *packer) packHeader(length int, fixType byte, fixLimit int, type16, type32 byte) {
+	switch {
+	case length < fixLimit:
+		p.buf = append(p.buf, fixType|byte(length))
+	case length <= math.MaxUint16:
+		p.buf = append(p.buf, type16)
+		p.buf = appendUint16(p.buf, uint16(length))
+	default:
+		p.buf = append(p.buf, type32)
+		p.buf = appendUint32(p.buf, uint32(length))
+	}
+}
+
+// maxPreallocLength is the maximum number of the list and map elements allocated
+// upfront, since the lengths in the headers are not trusted.
+const maxPreallocLength = 1024
+
+// maxDepth is the maximum nesting depth of the lists and maps.
+const maxDepth = 256
+
+// unpacker decodes the values from the Aerospike msgpack format.
+type unpacker struct {
+	data   []byte
+	offset int
+	depth  int
+}
+
+// unpack decodes a single value from the Aerospike msgpack format.
+func unpack(data []byte) (any, error) {
+	u := &unpacker{data: data}
+	v, err := u.unpack()
+	if err != nil {
+		return nil, err
+	}
+	if u.offset != len(data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidData, len(data)-u.offset)
+	}
+	return v, nil
+}
+
+//nolint:gocyclo,funlen
+func (u *unpacker) unpack() (any, error) {
+	b, err := u.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int(b), nil
+	case b >= 0xe0:
+		return int(int8(b)), nil
+	case b >= 0x80 && b <= 0x8f:
+		return u.unpackMap(int(b & 0x0f))
+	case b >= 0x90 && b <= 0x9f:
+		return u.unpackArray(int(b & 0x0f))
+	case b >= 0xa0 && b <= 0xbf:
+		return u.unpackString(int(b & 0x1f))
+	}
+
+	switch b {
+	case mpNil:
+		return nil, nil
+	case mpFalse:
+		return false, nil
+	case mpTrue:
+		return true, nil
+	case mpBin8, mpBin16, mpBin32:
+		length, err := u.readLength(b - mpBin8)
+		if err != nil {
+			return nil, err
+		}
+		data, err := u.read(length)
+		return copyBytes(data), err
+	case mpFloat32:
+		data, err := u.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), nil
+	case mpFloat64:
+		data, err := u.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+	case mpUint8, mpUint16, mpUint32, mpUint64:
+		data, err := u.read(1 << (b - mpUint8))
+		if err != nil {
+			return nil, err
+		}
+		i := readUint(data)
+		if i > math.MaxInt64 {
+			return i, nil
+		}
+		return int(i), nil
+	case mpInt8:
+		data, err := u.read(1)
+		if err != nil {
+			return nil, err
+		}
+		return int(int8(data[0])), nil
+	case mpInt16:
+		data, err := u.read(2)
+		if err != nil {
+			return nil, err
+		}
+		return int(int16(binary.BigEndian.Uint16(data))), nil
+	case mpInt32:
+		data, err := u.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return int(int32(binary.BigEndian.Uint32(data))), nil
+	case mpInt64:
+		data, err := u.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return int(int64(binary.BigEndian.Uint64(data))), nil
+	case mpStr8, mpStr16, mpStr32:
+		length, err := u.readLength(b - mpStr8)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackString(length)
+	case mpArray16, mpArray32:
+		length, err := u.readLength(b - mpArray16 + 1)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackArray(length)
+	case mpMap16, mpMap32:
+		length, err := u.readLength(b - mpMap16 + 1)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackMap(length)
+	default:
+		return nil, fmt.Errorf("%w: unexpected type byte 0x%02x", ErrUnsupportedType, b)
+	}
+}
+
+// unpackString decodes the string of the given length, prefixed with the particle type.
+func (u *unpacker) unpackString(length int) (any, error) {
+	data, err := u.read(length)
+	if err != nil || length == 0 {
+		return "", err
+	}
+
+	switch Type(data[0]) {
+	case String:
+		return string(data[1:]), nil
+	case GeoJSON:
+		return GeoJSONValue(data[1:]), nil
+	case HLL:
+		return HLLValue(copyBytes(data[1:])), nil
+	default:
+		return copyBytes(data[1:]), nil
+	}
+}
+
+// unpackArray decodes the array of the given length, skipping the ordered list
+// extension header.
+func (u *unpacker) unpackArray(length int) (any, error) {
+	// each element takes at least one byte
+	if err := u.enter(length, 1); err != nil {
+		return nil, err
+	}
+	defer u.leave()
+
+	if length > 0 && u.isExt() {
+		if _, err := u.readExt(); err != nil {
+			return nil, err
+		}
+		length--
+	}
+
+	list := make([]any, 0, preallocLength(length))
+	for i := 0; i < length; i++ {
+		v, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+
+	return list, nil
+}
+
+// unpackMap decodes the map of the given length. The ordered maps, marked by the
+// extension header, are decoded into []MapPair.
+func (u *unpacker) unpackMap(length int) (any, error) {
+	// each entry takes at least two bytes
+	if err := u.enter(length, 2); err != nil {
+		return nil, err
+	}
+	defer u.leave()
+
+	ordered := false
+	if length > 0 && u.isExt() {
+		flags, err := u.readExt()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := u.unpack(); err != nil { // the extension value
+			return nil, err
+		}
+		ordered = flags&mapKeyOrdered != 0
+		length--
+	}
+
+	if ordered {
+		pairs := make([]MapPair, 0, preallocLength(length))
+		for i := 0; i < length; i++ {
+			key, value, err := u.unpackPair()
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, MapPair{Key: key, Value: value})
+		}
+		return pairs, nil
+	}
+
+	m := make(map[any]any, preallocLength(length))
+	for i := 0; i < length; i++ {
+		key, value, err := u.unpackPair()
+		if err != nil {
+			return nil, err
+		}
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			return nil, fmt.Errorf("%w: map key of type %T", ErrUnsupportedType, key)
+		}
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+// enter checks the length of the list or map against the remaining data, given the
+// minimum size of an element, and the nesting depth of the value.
+func (u *unpacker) enter(length, elementSize int) error {
+	if length < 0 || length > (len(u.data)-u.offset)/elementSize {
+		return fmt.Errorf("%w: length %d exceeds the remaining data", ErrInvalidData, length)
+	}
+	if u.depth >= maxDepth {
+		return fmt.Errorf("%w: nesting depth exceeds %d", ErrInvalidData, maxDepth)
+	}
+	u.depth++
+	return nil
+}
+
+// leave decrements the nesting depth on leaving a list or map.
+func (u *unpacker) leave() {
+	u.depth--
+}
+
+// preallocLength returns the number of elements to allocate upfront for the length.
+func preallocLength(length int) int {
+	if length > maxPreallocLength {
+		return maxPreallocLength
+	}
+	return length
+}
+
+// unpackPair decodes a map key/value pair.
+func (u *unpacker) unpackPair() (any, any, error) {
+	key, err := u.unpack()
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := u.unpack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+// isExt reports whether the next value is an extension.
+func (u *unpacker) isExt() bool {
+	if u.offset >= len(u.data) {
+		return false
+	}
+	b := u.data[u.offset]
+	return (b >= mpExt8 && b <= mpExt32) || (b >= mpFixExt1 && b <= mpFixExt)
+}
+
+// readExt reads the extension and returns its type, which holds the CDT flags.
+func (u *unpacker) readExt() (byte, error) {
+	b, err := u.readByte()
+	if err != nil {
+		return 0, err
+	}
+
+	var length int
+	switch {
+	case b >= mpExt8 && b <= mpExt32:
+		if length, err = u.readLength(b - mpExt8); err != nil {
+			return 0, err
+		}
+	default:
+		length = 1 << (b - mpFixExt1)
+	}
+
+	extType, err := u.readByte()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := u.read(length); err != nil {
+		return 0, err
+	}
+
+	return extType, nil
+}
+
+// readLength reads the length of 1, 2 or 4 bytes for size 0, 1 or 2 respectively.
+func (u *unpacker) readLength(size byte) (int, error) {
+	data, err := u.read(1 << size)
+	if err != nil {
+		return 0, err
+	}
+	return int(readUint(data)), nil
+}
+
+// readByte reads the next byte.
+func (u *unpacker) readByte() (byte, error) {
+	data, err := u.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return data[0], nil
+}
+
+// read reads the next n bytes.
+func (u *unpacker) read(n int) ([]byte, error) {
+	if n < 0 || n > len(u.data)-u.offset {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidData)
+	}
+	data := u.data[u.offset : u.offset+n]
+	u.offset += n
+	return data, nil
+}
+
+// appendUint16 appends the big-endian bytes of the integer.
+func appendUint16(b []byte, i uint16) []byte {
+	return append(b, byte(i>>8), byte(i))
+}
+
+// appendUint32 appends the big-endian bytes of the integer.
+func appendUint32(b []byte, i uint32) []byte {
+	return append(b, byte(i>>24), byte(i>>16), byte(i>>8), byte(i))
+}
+
+// appendUint64 appends the big-endian bytes of the integer.
+func appendUint64(b []byte, i uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(i>>32)), uint32(i))
+}
+
+// readUint reads the big-endian unsigned integer of 1, 2, 4 or 8 bytes.
+func readUint(data []byte) uint64 {
+	switch len(data) {
+	case 1:
+		return uint64(data[0])
+	case 2:
+		return uint64(binary.BigEndian.Uint16(data))
+	case 4:
+		return uint64(binary.BigEndian.Uint32(data))
+	default:
+		return binary.BigEndian.Uint64(data)
+	}
+}
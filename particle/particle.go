@@ -0,0 +1,174 @@
+// Package particle encodes and decodes the Aerospike particle format of bin values,
+// including the msgpack based format of the list and map (CDT) values, without
+// depending on the Aerospike client library.
+package particle
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Type is the Aerospike particle type.
+type Type int
+
+// The Aerospike particle types.
+const (
+	Null    Type = 0
+	Integer Type = 1
+	Float   Type = 2
+	String  Type = 3
+	Blob    Type = 4
+	Bool    Type = 17
+	HLL     Type = 18
+	Map     Type = 19
+	List    Type = 20
+	GeoJSON Type = 23
+)
+
+// MapPair is a key/value pair of an ordered map. The ordered maps are decoded into
+// []MapPair to preserve the order, and []MapPair values are encoded as key ordered
+// maps, which requires the pairs to be sorted by key.
+type MapPair struct {
+	Key   any
+	Value any
+}
+
+// GeoJSONValue is a GeoJSON string encoded as the GeoJSON particle.
+type GeoJSONValue string
+
+// HLLValue is a HyperLogLog value encoded as the HLL particle.
+type HLLValue []byte
+
+// geoJSONHeaderSize is the size of the GeoJSON particle header, which consists of
+// the flags (1 byte) and the number of cells (2 bytes), which are computed by the server.
+const geoJSONHeaderSize = 3
+
+// Encode returns the particle type and the particle bytes of the value.
+// Supported values are nil, booleans, integers, floats, strings, []byte, GeoJSONValue,
+// HLLValue, slices, maps and []MapPair, where the elements of the slices and maps
+// are of the supported types.
+func Encode(v any) (Type, []byte, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return Null, nil, nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Invalid:
+		return Null, nil, nil
+	case reflect.Bool:
+		if value.Bool() {
+			return Bool, []byte{1}, nil
+		}
+		return Bool, []byte{0}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Integer, encodeInt(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return Null, nil, fmt.Errorf("%w: value %d overflows int64", ErrUnsupportedType,
+				value.Uint())
+		}
+		return Integer, encodeInt(int64(value.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, math.Float64bits(value.Float()))
+		return Float, data, nil
+	case reflect.String:
+		if value.Type() == geoJSONType {
+			data := make([]byte, geoJSONHeaderSize, geoJSONHeaderSize+value.Len())
+			return GeoJSON, append(data, value.String()...), nil
+		}
+		return String, []byte(value.String()), nil
+	case reflect.Slice, reflect.Array:
+		switch {
+		case value.Type() == hllType:
+			return HLL, copyBytes(value.Bytes()), nil
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+			return Blob, copyBytes(value.Bytes()), nil
+		case value.Type() == mapPairsType:
+			data, err := pack(value)
+			return Map, data, err
+		default:
+			data, err := pack(value)
+			return List, data, err
+		}
+	case reflect.Map:
+		data, err := pack(value)
+		return Map, data, err
+	default:
+		return Null, nil, fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type())
+	}
+}
+
+// Decode decodes the particle bytes of the given particle type.
+// Integers are decoded as int, floats as float64, strings as string, blobs as []byte,
+// lists as []any, unordered maps as map[any]any, and ordered maps as []MapPair.
+func Decode(particleType Type, data []byte) (any, error) {
+	switch particleType {
+	case Null:
+		return nil, nil
+	case Integer:
+		if len(data) != 8 {
+			return nil, fmt.Errorf("%w: integer of %d bytes", ErrInvalidData, len(data))
+		}
+		return int(int64(binary.BigEndian.Uint64(data))), nil
+	case Float:
+		if len(data) != 8 {
+			return nil, fmt.Errorf("%w: float of %d bytes", ErrInvalidData, len(data))
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+	case String:
+		return string(data), nil
+	case Blob:
+		return copyBytes(data), nil
+	case Bool:
+		if len(data) != 1 {
+			return nil, fmt.Errorf("%w: bool of %d bytes", ErrInvalidData, len(data))
+		}
+		return data[0] != 0, nil
+	case HLL:
+		return HLLValue(copyBytes(data)), nil
+	case GeoJSON:
+		if len(data) < geoJSONHeaderSize {
+			return nil, fmt.Errorf("%w: GeoJSON of %d bytes", ErrInvalidData, len(data))
+		}
+		ncells := int(binary.BigEndian.Uint16(data[1:geoJSONHeaderSize]))
+		offset := geoJSONHeaderSize + ncells*8
+		if len(data) < offset {
+			return nil, fmt.Errorf("%w: GeoJSON of %d bytes", ErrInvalidData, len(data))
+		}
+		return GeoJSONValue(data[offset:]), nil
+	case List, Map:
+		return unpack(data)
+	default:
+		return nil, fmt.Errorf("%w: particle type %d", ErrUnsupportedType, particleType)
+	}
+}
+
+var (
+	geoJSONType  = reflect.TypeOf(GeoJSONValue(""))
+	hllType      = reflect.TypeOf(HLLValue(nil))
+	mapPairsType = reflect.TypeOf([]MapPair(nil))
+)
+
+// encodeInt returns the big-endian bytes of the integer.
+func encodeInt(i int64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(i))
+	return data
+}
+
+// copyBytes returns a copy of the byte slice.
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
@@ -0,0 +1,181 @@
+package particle_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/particle"
+)
+
+func TestParticle_Encode(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        any
+		particleType particle.Type
+		data         []byte
+	}{
+		{"nil", nil, particle.Null, nil},
+		{"bool", true, particle.Bool, []byte{1}},
+		{"int", -2, particle.Integer, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}},
+		{"uint", uint8(1), particle.Integer, []byte{0, 0, 0, 0, 0, 0, 0, 1}},
+		{"float", 1.5, particle.Float, []byte{0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"string", "abc", particle.String, []byte("abc")},
+		{"blob", []byte{1, 2}, particle.Blob, []byte{1, 2}},
+		{"hll", particle.HLLValue{1, 2}, particle.HLL, []byte{1, 2}},
+		{"geojson", particle.GeoJSONValue("{}"), particle.GeoJSON, []byte{0, 0, 0, '{', '}'}},
+		{
+			name:         "list",
+			value:        []any{1, -1, 200, -200, "a", []byte{1}, nil, true, 1.5},
+			particleType: particle.List,
+			data: []byte{0x99, 0x01, 0xff, 0xcc, 0xc8, 0xd1, 0xff, 0x38, 0xa2, 0x03, 'a',
+				0xa2, 0x04, 0x01, 0xc0, 0xc3, 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name:         "map",
+			value:        map[string]int{"a": 70000},
+			particleType: particle.Map,
+			data:         []byte{0x81, 0xa2, 0x03, 'a', 0xce, 0x00, 0x01, 0x11, 0x70},
+		},
+		{
+			name:         "orderedMap",
+			value:        []particle.MapPair{{Key: "a", Value: 1}, {Key: "b", Value: 2}},
+			particleType: particle.Map,
+			data: []byte{0x83, 0xc7, 0x00, 0x01, 0xc0, 0xa2, 0x03, 'a', 0x01,
+				0xa2, 0x03, 'b', 0x02},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			particleType, data, err := particle.Encode(test.value)
+			assert.IsNil(t, err)
+			assert.Equal(t, particleType, test.particleType)
+			assert.Equal(t, data, test.data)
+		})
+	}
+
+	_, _, err := particle.Encode(struct{}{})
+	assert.ErrorIs(t, err, particle.ErrUnsupportedType)
+
+	_, _, err = particle.Encode(uint64(math.MaxUint64))
+	assert.ErrorIs(t, err, particle.ErrUnsupportedType)
+
+	_, _, err = particle.Encode([]any{struct{}{}})
+	assert.ErrorIs(t, err, particle.ErrUnsupportedType)
+}
+
+func TestParticle_RoundTrip(t *testing.T) {
+	long := strings.Repeat("a", 70000)
+	tests := []struct {
+		name     string
+		value    any
+		expected any
+	}{
+		{"int", math.MinInt64, math.MinInt64},
+		{"float", -1.25, -1.25},
+		{"string", "abc", "abc"},
+		{"bool", false, false},
+		{"geojson", particle.GeoJSONValue(`{"type":"Point"}`), particle.GeoJSONValue(`{"type":"Point"}`)},
+		{
+			name: "list",
+			value: []any{0, 127, 128, 255, 256, 65535, 65536, math.MaxUint32,
+				math.MaxUint32 + 1, -32, -33, -128, -129, -32768, -32769, math.MinInt32,
+				math.MinInt32 - 1, uint64(math.MaxUint64), float32(1.5)},
+			expected: []any{0, 127, 128, 255, 256, 65535, 65536, math.MaxUint32,
+				math.MaxUint32 + 1, -32, -33, -128, -129, -32768, -32769, math.MinInt32,
+				math.MinInt32 - 1, uint64(math.MaxUint64), 1.5},
+		},
+		{
+			name:     "nested",
+			value:    []any{[]any{"a", []byte{1}}, map[any]any{1: []any{2}}, particle.HLLValue{3}},
+			expected: []any{[]any{"a", []byte{1}}, map[any]any{1: []any{2}}, particle.HLLValue{3}},
+		},
+		{"large", []string{long}, []any{long}},
+		{
+			name:     "orderedMap",
+			value:    []particle.MapPair{{Key: "a", Value: []any{1}}, {Key: "b", Value: nil}},
+			expected: []particle.MapPair{{Key: "a", Value: []any{1}}, {Key: "b", Value: nil}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			particleType, data, err := particle.Encode(test.value)
+			assert.IsNil(t, err)
+
+			decoded, err := particle.Decode(particleType, data)
+			assert.IsNil(t, err)
+			assert.Equal(t, decoded, test.expected)
+		})
+	}
+}
+
+func TestParticle_Decode(t *testing.T) {
+	// ordered list with the extension header
+	decoded, err := particle.Decode(particle.List, []byte{0x93, 0xc7, 0x00, 0x01, 0x01, 0x02})
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, any([]any{1, 2}))
+
+	// unordered map with the extension header
+	decoded, err = particle.Decode(particle.Map, []byte{0x82, 0xc7, 0x00, 0x00, 0xc0, 0x01, 0x02})
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, any(map[any]any{1: 2}))
+
+	// GeoJSON with the server computed cells
+	decoded, err = particle.Decode(particle.GeoJSON, []byte{0, 0, 1, 1, 2, 3, 4, 5, 6, 7, 8, '{', '}'})
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, any(particle.GeoJSONValue("{}")))
+
+	tests := []struct {
+		name         string
+		particleType particle.Type
+		data         []byte
+		err          error
+	}{
+		{"integer", particle.Integer, []byte{1}, particle.ErrInvalidData},
+		{"float", particle.Float, []byte{1}, particle.ErrInvalidData},
+		{"bool", particle.Bool, nil, particle.ErrInvalidData},
+		{"geojson", particle.GeoJSON, []byte{0, 0, 1}, particle.ErrInvalidData},
+		{"truncated", particle.List, []byte{0x92, 0x01}, particle.ErrInvalidData},
+		{"trailing", particle.List, []byte{0x91, 0x01, 0x02}, particle.ErrInvalidData},
+		{"hugeList", particle.List, []byte{0xdd, 0x7f, 0xff, 0xff, 0xff}, particle.ErrInvalidData},
+		{"hugeMap", particle.Map, []byte{0xdf, 0x7f, 0xff, 0xff, 0xff}, particle.ErrInvalidData},
+		{"hugeOrderedMap", particle.Map,
+			[]byte{0xdf, 0x7f, 0xff, 0xff, 0xff, 0xc7, 0x00, 0x01, 0xc0}, particle.ErrInvalidData},
+		{"deepList", particle.List, []byte(strings.Repeat("\x91", 1000) + "\x01"),
+			particle.ErrInvalidData},
+		{"mapKey", particle.Map, []byte{0x81, 0x91, 0x01, 0x01}, particle.ErrUnsupportedType},
+		{"typeByte", particle.List, []byte{0x91, 0xc1}, particle.ErrUnsupportedType},
+		{"particleType", particle.Type(100), nil, particle.ErrUnsupportedType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := particle.Decode(test.particleType, test.data)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func FuzzParticle_Decode(f *testing.F) {
+	for _, v := range []any{
+		[]any{1, "a", []byte{1}, 1.5, true, nil},
+		map[string]any{"a": []any{1}, "b": map[int]string{1: "c"}},
+		[]particle.MapPair{{Key: "a", Value: 1}},
+	} {
+		_, data, err := particle.Encode(v)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte{0xdd, 0x7f, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// must not panic or allocate by the untrusted lengths
+		_, _ = particle.Decode(particle.List, data)
+		_, _ = particle.Decode(particle.Map, data)
+	})
+}
@@ -0,0 +1,71 @@
+package mapper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/reugn/aerospike-mapper-go/particle"
+)
+
+// GeoJSON is a GeoJSON document stored in a GeoJSON bin, which can be indexed and
+// queried with the geospatial filters. It is encoded as a particle.GeoJSONValue,
+// and can be decoded from GeoJSON and string bins.
+type GeoJSON string
+
+// GeoJSONPoint returns the GeoJSON Point with the given longitude and latitude.
+func GeoJSONPoint(lng, lat float64) GeoJSON {
+	return GeoJSON(`{"type":"Point","coordinates":` + formatPosition(lng, lat) + `}`)
+}
+
+// GeoJSONPolygon returns the GeoJSON Polygon with the given [longitude, latitude]
+// points. The ring is closed if the last point differs from the first one.
+func GeoJSONPolygon(points ...[2]float64) GeoJSON {
+	if len(points) > 0 && points[0] != points[len(points)-1] {
+		points = append(points[:len(points):len(points)], points[0])
+	}
+
+	positions := make([]string, len(points))
+	for i, point := range points {
+		positions[i] = formatPosition(point[0], point[1])
+	}
+	return GeoJSON(`{"type":"Polygon","coordinates":[[` + strings.Join(positions, ",") + `]]}`)
+}
+
+// GeoJSONCircle returns the Aerospike AeroCircle with the given center longitude and
+// latitude, and the radius in meters.
+func GeoJSONCircle(lng, lat, radius float64) GeoJSON {
+	return GeoJSON(`{"type":"AeroCircle","coordinates":[` + formatPosition(lng, lat) +
+		`,` + formatFloat(radius) + `]}`)
+}
+
+// String returns the GeoJSON document.
+func (g GeoJSON) String() string {
+	return string(g)
+}
+
+// HLL is a HyperLogLog value stored in an HLL bin. It is encoded as a particle.HLLValue,
+// and can be decoded from HLL and []byte bins.
+type HLL []byte
+
+// formatPosition returns the GeoJSON position with the given longitude and latitude.
+func formatPosition(lng, lat float64) string {
+	return "[" + formatFloat(lng) + "," + formatFloat(lat) + "]"
+}
+
+// formatFloat returns the shortest decimal representation of f.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// encodeParticle returns the GeoJSON and HLL values wrapped in the particle types,
+// which preserve the bin type on write. Other values are returned unchanged.
+func encodeParticle(value any) any {
+	switch v := value.(type) {
+	case GeoJSON:
+		return particle.GeoJSONValue(v)
+	case HLL:
+		return particle.HLLValue(v)
+	default:
+		return value
+	}
+}
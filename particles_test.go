@@ -0,0 +1,123 @@
+package mapper_test
+
+import (
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+	"github.com/reugn/aerospike-mapper-go/particle"
+)
+
+func TestGeoJSON(t *testing.T) {
+	assert.Equal(t, mapper.GeoJSONPoint(-122.5, 37.25),
+		`{"type":"Point","coordinates":[-122.5,37.25]}`)
+	assert.Equal(t, mapper.GeoJSONPolygon([2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1}),
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`)
+	assert.Equal(t, mapper.GeoJSONPolygon([2]float64{0, 0}, [2]float64{1, 1}, [2]float64{0, 0}),
+		`{"type":"Polygon","coordinates":[[[0,0],[1,1],[0,0]]]}`)
+	assert.Equal(t, mapper.GeoJSONCircle(-122.5, 37.25, 1000).String(),
+		`{"type":"AeroCircle","coordinates":[[-122.5,37.25],1000]}`)
+}
+
+func TestMapper_EncodeParticle(t *testing.T) {
+	area := mapper.GeoJSONCircle(1, 2, 3)
+	place := testtypes.Place{
+		Name:     "name1",
+		Location: mapper.GeoJSONPoint(1, 2),
+		Area:     &area,
+		Visitors: mapper.HLL{1, 2, 3},
+	}
+
+	record, err := mapper.Encode(&place)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["name"], any("name1"))
+	assert.Equal(t, record.Bins["location"], any(particle.GeoJSONValue(place.Location)))
+	assert.Equal(t, record.Bins["area"], any(particle.GeoJSONValue(area)))
+	assert.Equal(t, record.Bins["visitors"], any(particle.HLLValue{1, 2, 3}))
+
+	particleType, _, err := particle.Encode(record.Bins["location"])
+	assert.IsNil(t, err)
+	assert.Equal(t, particleType, particle.GeoJSON)
+
+	particleType, _, err = particle.Encode(record.Bins["visitors"])
+	assert.IsNil(t, err)
+	assert.Equal(t, particleType, particle.HLL)
+}
+
+func TestMapper_DecodeParticle(t *testing.T) {
+	point := mapper.GeoJSONPoint(1, 2)
+	tests := []struct {
+		name     string
+		location any
+		area     any
+		visitors any
+	}{
+		{
+			name:     "wrapped",
+			location: testtypes.NewGeoJSONValue(point.String()),
+			area:     testtypes.NewGeoJSONValue(point.String()),
+			visitors: testtypes.NewHLLValue([]byte{1, 2}),
+		},
+		{
+			name:     "particle",
+			location: particle.GeoJSONValue(point),
+			area:     particle.GeoJSONValue(point),
+			visitors: particle.HLLValue{1, 2},
+		},
+		{
+			name:     "raw",
+			location: point.String(),
+			area:     point.String(),
+			visitors: []byte{1, 2},
+		},
+		{
+			name:     "raw blob",
+			location: testtypes.EncodeRawBlobValue(particle.GeoJSONValue(point)),
+			area:     testtypes.EncodeRawBlobValue(particle.GeoJSONValue(point)),
+			visitors: testtypes.EncodeRawBlobValue(particle.HLLValue{1, 2}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record := &testtypes.Record{
+				Bins: testtypes.BinMap{
+					"location": test.location,
+					"area":     test.area,
+					"visitors": test.visitors,
+				},
+			}
+
+			var place testtypes.Place
+			err := mapper.Decode(record, &place)
+			assert.IsNil(t, err)
+			assert.Equal(t, place, testtypes.Place{
+				Location: point,
+				Area:     &point,
+				Visitors: mapper.HLL{1, 2},
+			})
+		})
+	}
+}
+
+func TestMapper_DecodeParticleConvert(t *testing.T) {
+	// the wrong-typed bins are converted to the named string types without a panic
+	var place testtypes.Place
+	err := mapper.Decode(&testtypes.Record{
+		Bins: testtypes.BinMap{"location": 5, "area": 1.5},
+	}, &place)
+	assert.IsNil(t, err)
+	assert.Equal(t, place.Location, mapper.GeoJSON("5"))
+	assert.Equal(t, *place.Area, mapper.GeoJSON("1.5"))
+
+	type regions struct {
+		Areas map[string]mapper.GeoJSON `aero:"areas"`
+	}
+	var decoded regions
+	err = mapper.Decode(&testtypes.Record{
+		Bins: testtypes.BinMap{"areas": map[any]any{"a": true, "b": 2}},
+	}, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded.Areas, map[string]mapper.GeoJSON{"a": "true", "b": "2"})
+}
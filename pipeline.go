@@ -0,0 +1,208 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+)
+
+// ItemError describes the failure to process a single item of a stream or a slice.
+type ItemError struct {
+	// Index is the position of the item in the input.
+	Index int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// pipelineConfig contains the processing pipeline configuration.
+type pipelineConfig struct {
+	workers    int
+	unordered  bool
+	bufferSize int
+	mapper     *Mapper
+}
+
+// PipelineOption is a configuration option for the parallel processing operations,
+// such as DecodeStream.
+type PipelineOption func(*pipelineConfig)
+
+// WithWorkers sets the number of goroutines processing the items concurrently.
+// Defaults to 1.
+func WithWorkers(workers int) PipelineOption {
+	return func(c *pipelineConfig) {
+		if workers > 0 {
+			c.workers = workers
+		}
+	}
+}
+
+// WithUnordered allows emitting the results in completion order rather than in the
+// input order, which avoids waiting for the slow items when processing in parallel.
+func WithUnordered() PipelineOption {
+	return func(c *pipelineConfig) {
+		c.unordered = true
+	}
+}
+
+// WithBufferSize sets the buffer size of the output channels. Defaults to 0.
+func WithBufferSize(size int) PipelineOption {
+	return func(c *pipelineConfig) {
+		if size > 0 {
+			c.bufferSize = size
+		}
+	}
+}
+
+// WithMapper sets the Mapper used to encode or decode the items.
+// Defaults to the Mapper used by the package level functions.
+func WithMapper(m *Mapper) PipelineOption {
+	return func(c *pipelineConfig) {
+		if m != nil {
+			c.mapper = m
+		}
+	}
+}
+
+// newPipelineConfig returns a new pipelineConfig with the options applied.
+func newPipelineConfig(opts []PipelineOption) *pipelineConfig {
+	config := &pipelineConfig{
+		workers: 1,
+		mapper:  defaultMapper,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// pipelineResult is the result of processing a single item.
+type pipelineResult[O any] struct {
+	index int
+	value O
+	err   error
+}
+
+// pipelineJob is a single item to be processed.
+type pipelineJob[I, O any] struct {
+	index int
+	item  I
+	done  chan pipelineResult[O]
+}
+
+// runPipeline processes the items read from the in channel using fn, with a bounded
+// number of concurrent workers, and passes the results to emit in the configured order.
+// It returns when the in channel is closed and all the items are processed, when emit
+// returns false, or when the context is cancelled.
+func runPipeline[I, O any](ctx context.Context, in <-chan I, config *pipelineConfig,
+	fn func(I) (O, error), emit func(pipelineResult[O]) bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan pipelineJob[I, O])
+	// pending holds the in-flight jobs in input order
+	pending := make(chan chan pipelineResult[O], config.workers)
+	// results holds the results in completion order
+	results := make(chan pipelineResult[O], config.workers)
+
+	// dispatch the items to the workers
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for index := 0; ; index++ {
+			var item I
+			var ok bool
+			select {
+			case item, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			job := pipelineJob[I, O]{index: index, item: item}
+			if !config.unordered {
+				job.done = make(chan pipelineResult[O], 1)
+				select {
+				case pending <- job.done:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// process the items
+	workersDone := make(chan struct{}, config.workers)
+	for i := 0; i < config.workers; i++ {
+		go func() {
+			defer func() { workersDone <- struct{}{} }()
+			for job := range jobs {
+				value, err := fn(job.item)
+				result := pipelineResult[O]{index: job.index, value: value, err: err}
+				if job.done != nil {
+					job.done <- result // buffered
+					continue
+				}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	if config.unordered {
+		go func() {
+			for i := 0; i < config.workers; i++ {
+				<-workersDone
+			}
+			close(results)
+		}()
+
+		for result := range results {
+			if !emit(result) {
+				return
+			}
+		}
+		return
+	}
+
+	for done := range pending {
+		select {
+		case result := <-done:
+			if !emit(result) {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send sends the value to the channel unless the context is cancelled.
+// It reports whether the value was sent.
+func send[T any](ctx context.Context, ch chan<- T, value T) bool {
+	select {
+	case ch <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,51 @@
+package mapper
+
+import "sync"
+
+// RecordPool is a pool of reusable records backed by sync.Pool.
+// Use it with EncodeInto to avoid allocating a new record and bins map for
+// every encoded value. RecordPool is safe for concurrent use.
+type RecordPool struct {
+	pool sync.Pool
+}
+
+// NewRecordPool returns a new RecordPool.
+func NewRecordPool() *RecordPool {
+	return &RecordPool{
+		pool: sync.Pool{
+			New: func() any {
+				return &Record{
+					Bins: make(map[string]any),
+				}
+			},
+		},
+	}
+}
+
+// Get returns an empty record from the pool, allocating a new one if required.
+func (p *RecordPool) Get() *Record {
+	return p.pool.Get().(*Record)
+}
+
+// Put resets the record and returns it to the pool.
+// The record must not be used after being returned.
+func (p *RecordPool) Put(record *Record) {
+	if record == nil {
+		return
+	}
+	resetRecord(record)
+	p.pool.Put(record)
+}
+
+// resetRecord resets the record fields, clearing and retaining the bins map.
+func resetRecord(record *Record) {
+	bins := record.Bins
+	if bins == nil {
+		bins = make(map[string]any)
+	} else {
+		for binName := range bins {
+			delete(bins, binName)
+		}
+	}
+	*record = Record{Bins: bins}
+}
@@ -0,0 +1,203 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// binNamesCache caches the bin names of the struct types.
+var binNamesCache sync.Map // map[reflect.Type][]string
+
+// Projection describes the bins to read from Aerospike to decode a value.
+// It can be used to populate BatchRead.BinNames and BatchRead.ReadAllBins, or as the
+// bin names argument of single-record get operations.
+type Projection struct {
+	// BinNames contains the names of the bins to read. An empty list with ReadAllBins
+	// set to false means only the record header (generation, expiration) is read.
+	BinNames []string
+	// ReadAllBins indicates that all the bins should be read.
+	ReadAllBins bool
+}
+
+// ProjectionOf returns the Projection for the type of v.
+// The Record and map[string]any types (or pointers to them) result in reading all bins.
+func ProjectionOf(v any) (*Projection, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == recordType || t == reflect.TypeOf(map[string]any{}) {
+		return &Projection{ReadAllBins: true}, nil
+	}
+
+	binNames, err := BinNames(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Projection{BinNames: binNames}, nil
+}
+
+// BinNames returns the ordered names of the bins the type of v decodes.
+// v must be a struct or a struct pointer, which can be nil. The fields tagged with
+// `omit` and metadata fields are excluded, and the bins of nested and embedded
+// structs are included in the order of declaration.
+func BinNames(v any) ([]string, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrInvalidSourceType
+	}
+
+	if cached, ok := binNamesCache.Load(t); ok {
+		return copyStrings(cached.([]string)), nil
+	}
+
+	var binNames []string
+	if err := collectBinNames(t, &binNames, make(map[string]struct{}),
+		make(map[reflect.Type]struct{})); err != nil {
+		return nil, err
+	}
+
+	binNamesCache.Store(t, binNames)
+	return copyStrings(binNames), nil
+}
+
+// collectBinNames appends the bin names of the struct type t to binNames.
+// The visiting map is used to guard against recursive types.
+func collectBinNames(t reflect.Type, binNames *[]string, seen map[string]struct{},
+	visiting map[reflect.Type]struct{}) error {
+	if _, ok := visiting[t]; ok {
+		return nil
+	}
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	fields, err := cachedFields(t)
+	if err != nil {
+		return err
+	}
+
+	for i := range fields {
+		tag := fields[i].tag
+		fieldType := t.Field(i).Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && !tag.serialized() {
+			if fieldType.String() == timeType {
+				continue
+			}
+			if err := collectBinNames(fieldType, binNames, seen, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !fields[i].tagged || tag.meta || tag.omit || tag.name == "" {
+			continue
+		}
+
+		if _, ok := seen[tag.name]; !ok {
+			seen[tag.name] = struct{}{}
+			*binNames = append(*binNames, tag.name)
+		}
+	}
+
+	return nil
+}
+
+// copyStrings returns a copy of the given string slice.
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	c := make([]string, len(s))
+	copy(c, s)
+	return c
+}
+
+// BinName returns the name of the bin the field is mapped to.
+// v must be a pointer to a struct, and field must be a pointer to a field of that struct,
+// or of its nested or embedded structs, e.g. BinName(&user, &user.Name).
+// Returns ErrFieldNotMapped if the field is not found or is not mapped to a bin.
+func BinName(v any, field any) (string, error) {
+	tag, err := fieldTag(v, field)
+	if err != nil {
+		return "", err
+	}
+	return tag.name, nil
+}
+
+// IsTransformed reports whether the value of the field is transformed on encode by
+// the encrypt, compress, json, gob or ordered tag options, so that writing the field
+// value to the bin as is, e.g. using a bin operation, bypasses the transformation.
+// v and field are specified as in BinName.
+func IsTransformed(v any, field any) (bool, error) {
+	tag, err := fieldTag(v, field)
+	if err != nil {
+		return false, err
+	}
+	return tag.transformed() || tag.ordered, nil
+}
+
+// fieldTag returns the parsed `aero` tag of the mapped field of the struct v.
+func fieldTag(v any, field any) (*tag, error) {
+	structPtr := reflect.ValueOf(v)
+	if structPtr.Kind() != reflect.Ptr || structPtr.IsNil() ||
+		structPtr.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidSourceType
+	}
+
+	fieldPtr := reflect.ValueOf(field)
+	if fieldPtr.Kind() != reflect.Ptr || fieldPtr.IsNil() {
+		return nil, fmt.Errorf("%w: field must be a non-nil pointer", ErrFieldNotMapped)
+	}
+
+	tag, err := findFieldTag(structPtr.Elem(), fieldPtr)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, fmt.Errorf("%w: %s field not found", ErrFieldNotMapped, fieldPtr.Type().Elem())
+	}
+
+	return tag, nil
+}
+
+// findFieldTag searches the struct value for the field with the address of fieldPtr,
+// and returns the tag of the field. Returns nil if the field is not found.
+func findFieldTag(structValue reflect.Value, fieldPtr reflect.Value) (*tag, error) {
+	fields, err := cachedFields(structValue.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range fields {
+		fieldValue := structValue.Field(i)
+		// compare both the address and the type, since the first field of a struct
+		// shares the address with the struct itself
+		if fieldValue.Type() == fieldPtr.Type().Elem() &&
+			fieldValue.Addr().Pointer() == fieldPtr.Pointer() {
+			tag := &fields[i].tag
+			if !fields[i].tagged || tag.meta || tag.omit || tag.name == "" {
+				return nil, fmt.Errorf("%w: %s", ErrFieldNotMapped,
+					structValue.Type().Field(i).Name)
+			}
+			return tag, nil
+		}
+
+		fieldValue = fieldValueDeref(structValue, i)
+		if fieldValue.Kind() == reflect.Struct && fieldValue.CanAddr() {
+			tag, err := findFieldTag(fieldValue, fieldPtr)
+			if tag != nil || err != nil {
+				return tag, err
+			}
+		}
+	}
+
+	return nil, nil
+}
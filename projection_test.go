@@ -0,0 +1,103 @@
+package mapper_test
+
+import (
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+type treeNode struct {
+	Value int       `aero:"value"`
+	Left  *treeNode `aero:"left"`
+	Item  testtypes.Item1
+}
+
+func TestMapper_BinNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected []string
+	}{
+		{
+			name:  "item",
+			value: testtypes.Item{},
+			expected: []string{"title", "name", "empty", "size", "length", "offset",
+				"description", "list", "dict"},
+		},
+		{
+			name:     "nilPointer",
+			value:    (*testtypes.User)(nil),
+			expected: []string{"name", "login_count"},
+		},
+		{
+			name:     "recursive",
+			value:    &treeNode{},
+			expected: []string{"value", "title"},
+		},
+		{
+			name:  "metadataOnly",
+			value: mapper.Metadata{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ { // verify the cached value
+				binNames, err := mapper.BinNames(test.value)
+				assert.IsNil(t, err)
+				assert.Equal(t, binNames, test.expected)
+			}
+		})
+	}
+
+	_, err := mapper.BinNames([]int{1})
+	assert.ErrorIs(t, err, mapper.ErrInvalidSourceType)
+}
+
+func TestMapper_ProjectionOf(t *testing.T) {
+	projection, err := mapper.ProjectionOf(&testtypes.User{})
+	assert.IsNil(t, err)
+	assert.Equal(t, *projection, mapper.Projection{BinNames: []string{"name", "login_count"}})
+
+	projection, err = mapper.ProjectionOf(&mapper.Record{})
+	assert.IsNil(t, err)
+	assert.Equal(t, *projection, mapper.Projection{ReadAllBins: true})
+
+	projection, err = mapper.ProjectionOf(map[string]any{})
+	assert.IsNil(t, err)
+	assert.Equal(t, *projection, mapper.Projection{ReadAllBins: true})
+}
+
+func TestMapper_BinName(t *testing.T) {
+	item := testtypes.Item{Item2: &testtypes.Item2{}}
+	other := 0
+
+	tests := []struct {
+		name     string
+		field    any
+		expected string
+		err      error
+	}{
+		{"field", &item.Length, "length", nil},
+		{"embedded", &item.Title, "title", nil},
+		{"embeddedPointer", &item.Item2.Size, "size", nil},
+		{"omit", &item.Label, "", mapper.ErrFieldNotMapped},
+		{"meta", &item.Generation, "", mapper.ErrFieldNotMapped},
+		{"struct", &item.Item1, "", mapper.ErrFieldNotMapped},
+		{"notFound", &other, "", mapper.ErrFieldNotMapped},
+		{"notPointer", item.Length, "", mapper.ErrFieldNotMapped},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			binName, err := mapper.BinName(&item, test.field)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, binName, test.expected)
+		})
+	}
+
+	_, err := mapper.BinName(item, &item.Length)
+	assert.ErrorIs(t, err, mapper.ErrInvalidSourceType)
+}
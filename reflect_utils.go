@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strconv"
 	"time"
+
+	"github.com/reugn/aerospike-mapper-go/particle"
 )
 
 // fieldValueDeref returns field i of the given struct value.
@@ -87,6 +89,26 @@ func structValue(value any) (reflect.Value, error) {
 	return structValue, nil
 }
 
+// asInterface returns v as the interface type T. If v is not a pointer, and only
+// the pointer to its type implements T, a pointer to a copy of v is returned.
+func asInterface[T any](v any) (T, bool) {
+	if i, ok := v.(T); ok {
+		return i, true
+	}
+
+	value := reflect.ValueOf(v)
+	if value.IsValid() && value.Kind() != reflect.Ptr {
+		ptrValue := reflect.New(value.Type())
+		ptrValue.Elem().Set(value)
+		if i, ok := ptrValue.Interface().(T); ok {
+			return i, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
 // isEmptyValue reports whether v is the zero value for its type.
 func isEmptyValue(v reflect.Value) bool {
 	switch v.Kind() {
@@ -116,6 +138,50 @@ func isEmptyValue(v reflect.Value) bool {
 	return false // consider as non-empty for other types
 }
 
+// rawBlob returns the particle type and the data of v, if v is a struct holding a raw
+// particle value in the ParticleType and Data fields, like the client RawBlobValue.
+func rawBlob(v reflect.Value) (int, []byte, bool) {
+	if v.Kind() != reflect.Struct || v.NumField() != 2 {
+		return 0, nil, false
+	}
+
+	particleType := v.FieldByName("ParticleType")
+	data := v.FieldByName("Data")
+	if !particleType.IsValid() || !particleType.CanInt() || !data.IsValid() ||
+		data.Type() != reflect.TypeOf([]byte(nil)) {
+		return 0, nil, false
+	}
+
+	return int(particleType.Int()), data.Bytes(), true
+}
+
+// isDeleteBin reports whether v holds the DeleteBin sentinel value.
+func isDeleteBin(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	return v.IsValid() && v.Type() == reflect.TypeOf(DeleteBin)
+}
+
+// isIntegerKind reports whether k is a signed or unsigned integer kind.
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isEmptyArray reports whether v is an array with all the elements set to zero values.
+func isEmptyArray(v reflect.Value) bool {
+	return v.Kind() == reflect.Array && v.IsZero()
+}
+
 // convertElementType converts a value from a source field to the target type.
 //
 //nolint:gocyclo,funlen
@@ -161,18 +227,29 @@ func convertElementType(source any, targetType reflect.Type) (reflect.Value, err
 		return sourceValue, nil
 	}
 
+	// decode the raw particle values, e.g. the client RawBlobValue
+	if particleType, data, ok := rawBlob(sourceValue); ok {
+		decoded, err := particle.Decode(particle.Type(particleType), data)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return convertElementType(decoded, targetType)
+	}
+
 	switch targetType.Kind() {
 	case reflect.String:
 		// convert various types to string
 		switch sourceType.Kind() {
 		case reflect.String:
-			return sourceValue, nil
+			// convert between the named string types, e.g. GeoJSON
+			return sourceValue.Convert(targetType), nil
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return reflect.ValueOf(strconv.FormatInt(sourceValue.Int(), 10)), nil
+			return reflect.ValueOf(strconv.FormatInt(sourceValue.Int(), 10)).Convert(targetType), nil
 		case reflect.Float32, reflect.Float64:
-			return reflect.ValueOf(strconv.FormatFloat(sourceValue.Float(), 'f', -1, 64)), nil
+			return reflect.ValueOf(strconv.FormatFloat(sourceValue.Float(), 'f', -1, 64)).
+				Convert(targetType), nil
 		case reflect.Bool:
-			return reflect.ValueOf(strconv.FormatBool(sourceValue.Bool())), nil
+			return reflect.ValueOf(strconv.FormatBool(sourceValue.Bool())).Convert(targetType), nil
 		default:
 			return reflect.Value{}, fmt.Errorf("cannot convert %s to string", sourceType.String())
 		}
@@ -236,6 +313,17 @@ func convertElementType(source any, targetType reflect.Type) (reflect.Value, err
 		}
 
 	case reflect.Slice:
+		// convert maps to ordered maps, sorting the entries by key
+		if sourceType.Kind() == reflect.Map && isMapPairSlice(targetType) {
+			return convertToPairs(sourceValue, targetType)
+		}
+
+		// convert between the named byte slice types, e.g. HLL
+		if sourceType.Kind() == reflect.Slice && sourceType.Elem().Kind() == reflect.Uint8 &&
+			sourceType.ConvertibleTo(targetType) {
+			return sourceValue.Convert(targetType), nil
+		}
+
 		// handle slice conversion; requires element-by-element conversion
 		if sourceType.Kind() != reflect.Slice {
 			return reflect.Value{}, fmt.Errorf("cannot convert %s to slice", sourceType.String())
@@ -257,6 +345,11 @@ func convertElementType(source any, targetType reflect.Type) (reflect.Value, err
 		return newSlice, nil
 
 	case reflect.Map:
+		// convert ordered maps returned as MapPair slices
+		if isMapPairSlice(sourceType) {
+			return convertToMap(sourceValue, targetType)
+		}
+
 		// handle map conversion; requires key and value conversion
 		if sourceType.Kind() != reflect.Map {
 			return reflect.Value{}, fmt.Errorf("cannot convert %s to map", sourceType.String())
@@ -308,6 +401,9 @@ func convertElementType(source any, targetType reflect.Type) (reflect.Value, err
 				return reflect.Value{}, fmt.Errorf("cannot convert %s to %s",
 					sourceType.String(), timeType)
 			}
+		} else if sourceType.Kind() == reflect.Map || isMapPairSlice(sourceType) {
+			// map the entries of maps and ordered maps to the struct fields
+			return convertMapToStruct(sourceValue, targetType)
 		} else { // handle nested structs; recursively convert the nested struct
 			// create a new instance of the target struct
 			nestedValue := reflect.New(targetType).Elem()
@@ -320,6 +416,16 @@ func convertElementType(source any, targetType reflect.Type) (reflect.Value, err
 			return nestedValue, nil
 		}
 
+	case reflect.Interface:
+		// assign the source value if it implements the target interface
+		if !sourceType.Implements(targetType) {
+			return reflect.Value{}, fmt.Errorf("%s does not implement %s",
+				sourceType.String(), targetType.String())
+		}
+		convertedValue := reflect.New(targetType).Elem()
+		convertedValue.Set(sourceValue)
+		return convertedValue, nil
+
 	case reflect.Ptr:
 		// handle pointer conversion; create a new pointer to the target type
 		// and recursively convert the underlying value
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"errors"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+)
+
+var (
+	// ErrRecordNotFound is the same sentinel as mapper.ErrRecordNotFound, so that
+	// errors.Is matches the not found errors against either of them.
+	ErrRecordNotFound     = mapper.ErrRecordNotFound
+	ErrRecordExists       = errors.New("record already exists")
+	ErrGenerationMismatch = errors.New("record generation mismatch")
+)
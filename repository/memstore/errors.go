@@ -0,0 +1,7 @@
+package memstore
+
+import "errors"
+
+var (
+	ErrNamespaceNotFound = errors.New("namespace not found")
+)
@@ -0,0 +1,369 @@
+// Package memstore provides an in-memory repository.Store implementation with
+// Aerospike record semantics, intended for testing.
+package memstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/deepcopy"
+	"github.com/reugn/aerospike-mapper-go/repository"
+)
+
+// maxGeneration is the maximum record generation; the generation wraps around
+// to 1 when exceeded.
+const maxGeneration = 0xffff
+
+// entry represents a stored record.
+type entry struct {
+	setName    string
+	userKey    any
+	bins       map[string]any
+	generation uint32
+	voidTime   time.Time // zero value means never expire
+}
+
+// Store is an in-memory repository.Store implementation. Records are organized by
+// namespace and keyed by the digest, which is computed from the set name and the
+// user key as Aerospike does. The store supports the record generation and TTL
+// semantics, and honors the write intent generation policy and record exists action.
+// The stored and returned bin values are deep copies of the given ones.
+type Store struct {
+	mu         sync.RWMutex
+	clock      func() time.Time
+	defaultTTL time.Duration
+	namespaces map[string]map[[20]byte]*entry
+	strict     bool
+}
+
+var _ repository.Store = (*Store)(nil)
+
+// Option is a Store configuration option.
+type Option func(*Store)
+
+// WithClock sets the clock used to evaluate the record expiration.
+// Defaults to time.Now.
+func WithClock(clock func() time.Time) Option {
+	return func(s *Store) {
+		s.clock = clock
+	}
+}
+
+// WithDefaultTTL sets the namespace default TTL applied to the records written with
+// mapper.ExpirationNamespaceDefault. A zero value, which is the default, means
+// the records never expire.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		s.defaultTTL = ttl
+	}
+}
+
+// WithNamespaces restricts the store to the given namespaces. Operations on other
+// namespaces fail with ErrNamespaceNotFound. By default, any namespace is accepted.
+func WithNamespaces(namespaces ...string) Option {
+	return func(s *Store) {
+		s.strict = true
+		for _, namespace := range namespaces {
+			s.namespaces[namespace] = make(map[[20]byte]*entry)
+		}
+	}
+}
+
+// New returns a new empty Store configured with the given options.
+func New(opts ...Option) *Store {
+	store := &Store{
+		clock:      time.Now,
+		namespaces: make(map[string]map[[20]byte]*entry),
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+// Get returns the record for the given key.
+// Returns repository.ErrRecordNotFound if the record does not exist or has expired.
+func (s *Store) Get(ctx context.Context, key *repository.Key,
+	binNames ...string) (*mapper.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records, digest, err := s.lookup(key, false)
+	if err != nil {
+		return nil, err
+	}
+
+	e := s.live(records, digest)
+	if e == nil {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	return s.record(key.Namespace, digest, e, binNames), nil
+}
+
+// Put writes the record bins according to the given write intent.
+// Bins with nil values are deleted, and a record left without bins is deleted.
+func (s *Store) Put(ctx context.Context, record *mapper.Record,
+	intent *mapper.WriteIntent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if intent == nil {
+		intent = &mapper.WriteIntent{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := repository.KeyOf(record)
+	records, digest, err := s.lookup(key, true)
+	if err != nil {
+		return err
+	}
+
+	existing := s.live(records, digest)
+	if err := checkIntent(existing, intent); err != nil {
+		return err
+	}
+
+	e := &entry{
+		setName: record.SetName,
+		userKey: record.UserKey,
+		bins:    make(map[string]any, len(record.Bins)),
+	}
+	if existing != nil {
+		e.generation = existing.generation
+		e.voidTime = existing.voidTime
+		if e.userKey == nil {
+			e.userKey = existing.userKey
+		}
+		if intent.RecordExistsAction == mapper.RecordUpdate ||
+			intent.RecordExistsAction == mapper.RecordUpdateOnly {
+			for binName, binValue := range existing.bins {
+				e.bins[binName] = binValue
+			}
+		}
+	}
+
+	for binName, binValue := range record.Bins {
+		if binValue == nil {
+			delete(e.bins, binName)
+		} else {
+			e.bins[binName] = deepcopy.Copy(binValue)
+		}
+	}
+
+	if len(e.bins) == 0 {
+		delete(records, digest)
+		return nil
+	}
+
+	e.generation = e.generation%maxGeneration + 1
+	e.voidTime = s.voidTime(record.Expiration, existing)
+	records[digest] = e
+
+	return nil
+}
+
+// Delete deletes the record for the given key, and reports whether the record existed.
+func (s *Store) Delete(ctx context.Context, key *repository.Key) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, digest, err := s.lookup(key, false)
+	if err != nil {
+		return false, err
+	}
+
+	existed := s.live(records, digest) != nil
+	delete(records, digest)
+
+	return existed, nil
+}
+
+// Exists reports whether the record for the given key exists.
+func (s *Store) Exists(ctx context.Context, key *repository.Key) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records, digest, err := s.lookup(key, false)
+	if err != nil {
+		return false, err
+	}
+
+	return s.live(records, digest) != nil, nil
+}
+
+// BatchGet returns the records for the given keys in the same order, with nil
+// entries for the records that do not exist.
+func (s *Store) BatchGet(ctx context.Context, keys []*repository.Key,
+	binNames ...string) ([]*mapper.Record, error) {
+	records := make([]*mapper.Record, len(keys))
+	for i, key := range keys {
+		record, err := s.Get(ctx, key, binNames...)
+		switch {
+		case err == nil:
+			records[i] = record
+		case errors.Is(err, repository.ErrRecordNotFound):
+		default:
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// lookup returns the namespace records and the digest for the given key.
+// If create is true, the namespace records map is created if it does not exist,
+// which requires holding the write lock. Otherwise, a nil map is returned for
+// a missing namespace, so that it can be looked up under the read lock.
+func (s *Store) lookup(key *repository.Key, create bool) (map[[20]byte]*entry, [20]byte,
+	error) {
+	digest := key.Digest
+	if digest == [20]byte{} {
+		var err error
+		if digest, err = mapper.ComputeDigest(key.SetName, key.UserKey); err != nil {
+			return nil, digest, err
+		}
+	}
+
+	records, ok := s.namespaces[key.Namespace]
+	if !ok {
+		if s.strict {
+			return nil, digest, fmt.Errorf("%w: %s", ErrNamespaceNotFound, key.Namespace)
+		}
+		if !create {
+			return nil, digest, nil
+		}
+		records = make(map[[20]byte]*entry)
+		s.namespaces[key.Namespace] = records
+	}
+
+	return records, digest, nil
+}
+
+// live returns the entry for the given digest if it exists and has not expired.
+func (s *Store) live(records map[[20]byte]*entry, digest [20]byte) *entry {
+	e, ok := records[digest]
+	if !ok || s.expired(e) {
+		return nil
+	}
+	return e
+}
+
+// expired reports whether the entry has expired.
+func (s *Store) expired(e *entry) bool {
+	return !e.voidTime.IsZero() && !s.clock().Before(e.voidTime)
+}
+
+// voidTime returns the expiration time of the written record.
+func (s *Store) voidTime(expiration uint32, existing *entry) time.Time {
+	switch expiration {
+	case mapper.ExpirationNamespaceDefault:
+		if s.defaultTTL == 0 {
+			return time.Time{}
+		}
+		return s.clock().Add(s.defaultTTL)
+	case mapper.ExpirationNever:
+		return time.Time{}
+	case mapper.ExpirationDontUpdate:
+		if existing != nil {
+			return existing.voidTime
+		}
+		return s.voidTime(mapper.ExpirationNamespaceDefault, nil)
+	default:
+		return s.clock().Add(time.Duration(expiration) * time.Second)
+	}
+}
+
+// record returns the record representation of the entry.
+func (s *Store) record(namespace string, digest [20]byte, e *entry,
+	binNames []string) *mapper.Record {
+	record := &mapper.Record{
+		Key: mapper.Key{
+			Namespace: namespace,
+			SetName:   e.setName,
+			Digest:    digest,
+		},
+		KeyValue: mapper.KeyValue{
+			UserKey: e.userKey,
+		},
+		Metadata: mapper.Metadata{
+			Generation: e.generation,
+			Expiration: mapper.ExpirationNever,
+		},
+		Bins: make(map[string]any, len(e.bins)),
+	}
+
+	if !e.voidTime.IsZero() {
+		// round up the remaining time to seconds
+		ttl := e.voidTime.Sub(s.clock())
+		record.Expiration = uint32((ttl + time.Second - 1) / time.Second)
+	}
+
+	if len(binNames) == 0 {
+		for binName, binValue := range e.bins {
+			record.Bins[binName] = deepcopy.Copy(binValue)
+		}
+	} else {
+		for _, binName := range binNames {
+			if binValue, ok := e.bins[binName]; ok {
+				record.Bins[binName] = deepcopy.Copy(binValue)
+			}
+		}
+	}
+
+	return record
+}
+
+// checkIntent verifies the write intent against the existing entry.
+func checkIntent(existing *entry, intent *mapper.WriteIntent) error {
+	switch intent.RecordExistsAction {
+	case mapper.RecordUpdateOnly, mapper.RecordReplaceOnly:
+		if existing == nil {
+			return repository.ErrRecordNotFound
+		}
+	case mapper.RecordCreateOnly:
+		if existing != nil {
+			return repository.ErrRecordExists
+		}
+	default:
+	}
+
+	var generation uint32
+	if existing != nil {
+		generation = existing.generation
+	}
+
+	switch intent.GenerationPolicy {
+	case mapper.GenerationExpectEqual:
+		if intent.Generation != generation {
+			return fmt.Errorf("%w: expected %d, actual %d", repository.ErrGenerationMismatch,
+				intent.Generation, generation)
+		}
+	case mapper.GenerationExpectGreater:
+		if intent.Generation <= generation {
+			return fmt.Errorf("%w: expected %d is not greater than actual %d",
+				repository.ErrGenerationMismatch, intent.Generation, generation)
+		}
+	default:
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+package memstore_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+	"github.com/reugn/aerospike-mapper-go/repository"
+	"github.com/reugn/aerospike-mapper-go/repository/memstore"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newRecord(userKey any, bins map[string]any) *mapper.Record {
+	return &mapper.Record{
+		Key:      mapper.Key{Namespace: "test", SetName: "set1"},
+		KeyValue: mapper.KeyValue{UserKey: userKey},
+		Bins:     bins,
+	}
+}
+
+func TestStore_PutGet(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	key := repository.NewKey("test", "set1", "key1")
+
+	err := store.Put(ctx, newRecord("key1", map[string]any{"a": 1, "b": []int{1}}), nil)
+	assert.IsNil(t, err)
+
+	record, err := store.Get(ctx, key)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Generation, 1)
+	assert.Equal(t, record.Expiration, mapper.ExpirationNever)
+	assert.Equal(t, record.UserKey, any("key1"))
+	assert.Equal(t, record.Bins, map[string]any{"a": 1, "b": []int{1}})
+
+	digest, err := mapper.ComputeDigest("set1", "key1")
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Digest, digest)
+
+	// the returned bins are copies
+	record.Bins["b"].([]int)[0] = 2
+
+	// update merges the bins, nil values delete bins
+	err = store.Put(ctx, newRecord("key1", map[string]any{"a": nil, "c": "c"}), nil)
+	assert.IsNil(t, err)
+
+	record, err = store.Get(ctx, key)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Generation, 2)
+	assert.Equal(t, record.Bins, map[string]any{"b": []int{1}, "c": "c"})
+
+	// bin projection
+	record, err = store.Get(ctx, key, "c", "d")
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins, map[string]any{"c": "c"})
+
+	// replace
+	err = store.Put(ctx, newRecord("key1", map[string]any{"d": 1.5}),
+		&mapper.WriteIntent{RecordExistsAction: mapper.RecordReplace})
+	assert.IsNil(t, err)
+
+	records, err := store.BatchGet(ctx, []*repository.Key{key,
+		repository.NewKey("test", "set1", "key2")})
+	assert.IsNil(t, err)
+	assert.Equal(t, records[0].Bins, map[string]any{"d": 1.5})
+	assert.Equal(t, records[0].Generation, 3)
+	assert.IsNil(t, records[1])
+
+	// deleting all bins deletes the record
+	err = store.Put(ctx, newRecord("key1", map[string]any{"d": nil}), nil)
+	assert.IsNil(t, err)
+
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound)
+}
+
+func TestStore_WriteIntent(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+
+	tests := []struct {
+		name     string
+		intent   mapper.WriteIntent
+		expected error
+	}{
+		{
+			name:     "updateOnlyNotFound",
+			intent:   mapper.WriteIntent{RecordExistsAction: mapper.RecordUpdateOnly},
+			expected: repository.ErrRecordNotFound,
+		},
+		{
+			name:     "replaceOnlyNotFound",
+			intent:   mapper.WriteIntent{RecordExistsAction: mapper.RecordReplaceOnly},
+			expected: repository.ErrRecordNotFound,
+		},
+		{
+			name:   "create",
+			intent: mapper.WriteIntent{RecordExistsAction: mapper.RecordCreateOnly},
+		},
+		{
+			name:     "createExists",
+			intent:   mapper.WriteIntent{RecordExistsAction: mapper.RecordCreateOnly},
+			expected: repository.ErrRecordExists,
+		},
+		{
+			name: "generationMismatch",
+			intent: mapper.WriteIntent{
+				Generation:       2,
+				GenerationPolicy: mapper.GenerationExpectEqual,
+			},
+			expected: repository.ErrGenerationMismatch,
+		},
+		{
+			name: "generationEqual",
+			intent: mapper.WriteIntent{
+				Generation:         1,
+				GenerationPolicy:   mapper.GenerationExpectEqual,
+				RecordExistsAction: mapper.RecordUpdateOnly,
+			},
+		},
+		{
+			name: "generationNotGreater",
+			intent: mapper.WriteIntent{
+				Generation:       2,
+				GenerationPolicy: mapper.GenerationExpectGreater,
+			},
+			expected: repository.ErrGenerationMismatch,
+		},
+		{
+			name: "generationGreater",
+			intent: mapper.WriteIntent{
+				Generation:       5,
+				GenerationPolicy: mapper.GenerationExpectGreater,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := store.Put(ctx, newRecord(1, map[string]any{"a": 1}), &test.intent)
+			assert.ErrorIs(t, err, test.expected)
+		})
+	}
+}
+
+func TestStore_Expiration(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := memstore.New(memstore.WithClock(clock.Now),
+		memstore.WithDefaultTTL(time.Hour))
+	key := repository.NewKey("test", "set1", "key1")
+
+	record := newRecord("key1", map[string]any{"a": 1})
+	record.Expiration = 10
+	err := store.Put(ctx, record, nil)
+	assert.IsNil(t, err)
+
+	clock.now = clock.now.Add(5500 * time.Millisecond)
+	fetched, err := store.Get(ctx, key)
+	assert.IsNil(t, err)
+	assert.Equal(t, fetched.Expiration, 5)
+
+	// preserve the TTL on update
+	record.Expiration = mapper.ExpirationDontUpdate
+	err = store.Put(ctx, record, nil)
+	assert.IsNil(t, err)
+
+	clock.now = clock.now.Add(5 * time.Second)
+	exists, err := store.Exists(ctx, key)
+	assert.IsNil(t, err)
+	assert.Equal(t, exists, false)
+
+	// the namespace default TTL
+	record.Expiration = mapper.ExpirationNamespaceDefault
+	err = store.Put(ctx, record, &mapper.WriteIntent{
+		RecordExistsAction: mapper.RecordCreateOnly,
+	})
+	assert.IsNil(t, err)
+
+	fetched, err = store.Get(ctx, key)
+	assert.IsNil(t, err)
+	assert.Equal(t, fetched.Generation, 1)
+	assert.Equal(t, fetched.Expiration, 3600)
+
+	deleted, err := store.Delete(ctx, key)
+	assert.IsNil(t, err)
+	assert.Equal(t, deleted, true)
+
+	deleted, err = store.Delete(ctx, key)
+	assert.IsNil(t, err)
+	assert.Equal(t, deleted, false)
+}
+
+func TestStore_Namespaces(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New(memstore.WithNamespaces("test"))
+
+	err := store.Put(ctx, newRecord("key1", map[string]any{"a": 1}), nil)
+	assert.IsNil(t, err)
+
+	_, err = store.Exists(ctx, repository.NewKey("test2", "set1", "key1"))
+	assert.ErrorIs(t, err, memstore.ErrNamespaceNotFound)
+
+	_, err = store.Exists(ctx, repository.NewKey("test", "set1", 1.5))
+	assert.ErrorIs(t, err, mapper.ErrInvalidUserKey)
+}
+
+func TestStore_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+
+	const n = 16
+	// build the keys upfront, since the fmt buffer pool synchronizes the goroutines,
+	// which hides the data races from the race detector
+	keys := make([][]*repository.Key, n)
+	for i := range keys {
+		for j := 0; j < 50; j++ {
+			keys[i] = append(keys[i], &repository.Key{Key: mapper.Key{
+				Namespace: fmt.Sprintf("ns%d-%d", i, j),
+				Digest:    [20]byte{1},
+			}})
+		}
+	}
+
+	start := make(chan struct{})
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			// the reads of missing namespaces do not create them
+			for _, key := range keys[i] {
+				if _, err := store.Exists(ctx, key); err != nil {
+					errs[i] = err
+					return
+				}
+				if _, err := store.Get(ctx, key); !errors.Is(err, repository.ErrRecordNotFound) {
+					errs[i] = fmt.Errorf("unexpected get error: %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.IsNil(t, err)
+	}
+
+	record := newRecord("key1", map[string]any{"a": 1})
+	record.Namespace = "ns0-0"
+	err := store.Put(ctx, record, nil)
+	assert.IsNil(t, err)
+
+	exists, err := store.Exists(ctx, repository.NewKey("ns0-0", "set1", "key1"))
+	assert.IsNil(t, err)
+	assert.Equal(t, exists, true)
+
+	deleted, err := store.Delete(ctx, repository.NewKey("ns9", "set1", "key1"))
+	assert.IsNil(t, err)
+	assert.Equal(t, deleted, false)
+}
+
+func TestStore_Repository(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.New[testtypes.User](memstore.New(), "test", "users")
+
+	user := &testtypes.User{
+		KeyValue: mapper.KeyValue{UserKey: "user1"},
+		Name:     "name1",
+	}
+	err := repo.Put(ctx, user)
+	assert.IsNil(t, err)
+
+	// the record already exists
+	err = repo.Put(ctx, user)
+	assert.ErrorIs(t, err, repository.ErrRecordExists)
+
+	fetched, err := repo.Get(ctx, "user1")
+	assert.IsNil(t, err)
+	assert.Equal(t, fetched.Generation, 1)
+
+	// compare-and-set update
+	fetched.LoginCount++
+	err = repo.Put(ctx, fetched)
+	assert.IsNil(t, err)
+
+	// stale generation
+	err = repo.Put(ctx, fetched)
+	assert.ErrorIs(t, err, repository.ErrGenerationMismatch)
+
+	fetched, err = repo.Get(ctx, "user1")
+	assert.IsNil(t, err)
+	assert.Equal(t, fetched.Generation, 2)
+	assert.Equal(t, fetched.LoginCount, 1)
+}
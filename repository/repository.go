@@ -0,0 +1,151 @@
+// Package repository provides a client-agnostic data access layer on top of the
+// mapper encoding and decoding operations.
+package repository
+
+import (
+	"context"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+)
+
+// Repository maps the values of type T to records of the underlying Store.
+// T must be a struct type with fields tagged using the `aero` tag.
+type Repository[T any] struct {
+	store     Store
+	mapper    *mapper.Mapper
+	namespace string
+	setName   string
+}
+
+// Option configures the Repository.
+type Option func(*options)
+
+// options contains the Repository configuration.
+type options struct {
+	mapper *mapper.Mapper
+}
+
+// WithMapper sets the Mapper used to encode and decode the values, e.g. a Mapper
+// configured with a Cipher. Defaults to mapper.New().
+func WithMapper(m *mapper.Mapper) Option {
+	return func(o *options) {
+		o.mapper = m
+	}
+}
+
+// New returns a new Repository for the given store. The namespace and set name
+// are used to build the record keys and as defaults for the encoded records.
+func New[T any](store Store, namespace, setName string, opts ...Option) *Repository[T] {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.mapper == nil {
+		o.mapper = mapper.New()
+	}
+
+	return &Repository[T]{
+		store:     store,
+		mapper:    o.mapper,
+		namespace: namespace,
+		setName:   setName,
+	}
+}
+
+// Key returns the record Key for the given user key.
+func (r *Repository[T]) Key(userKey any) *Key {
+	return NewKey(r.namespace, r.setName, userKey)
+}
+
+// Get reads the record for the given user key and decodes it into a new value of T.
+// Only the bins mapped by T are read. Returns ErrRecordNotFound if the record does not exist.
+func (r *Repository[T]) Get(ctx context.Context, userKey any) (*T, error) {
+	binNames, err := mapper.BinNames((*T)(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	key := r.Key(userKey)
+	record, err := r.store.Get(ctx, key, binNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.decode(key, record)
+}
+
+// Put encodes the item and writes it to the store.
+// If the item maps the record generation, the write is conditioned on it as
+// described in mapper.EncodeForUpdate.
+func (r *Repository[T]) Put(ctx context.Context, item *T) error {
+	record, intent, err := r.mapper.EncodeForUpdate(item)
+	if err != nil {
+		return err
+	}
+
+	if record.Namespace == "" {
+		record.Namespace = r.namespace
+	}
+	if record.SetName == "" {
+		record.SetName = r.setName
+	}
+
+	return r.store.Put(ctx, record, intent)
+}
+
+// Delete deletes the record for the given user key, and reports whether
+// the record existed.
+func (r *Repository[T]) Delete(ctx context.Context, userKey any) (bool, error) {
+	return r.store.Delete(ctx, r.Key(userKey))
+}
+
+// Exists reports whether the record for the given user key exists.
+func (r *Repository[T]) Exists(ctx context.Context, userKey any) (bool, error) {
+	return r.store.Exists(ctx, r.Key(userKey))
+}
+
+// BatchGet reads the records for the given user keys and decodes them into values
+// of T. Only the bins mapped by T are read. The returned slice has the same order as
+// the user keys, with nil entries for the records that do not exist.
+func (r *Repository[T]) BatchGet(ctx context.Context, userKeys ...any) ([]*T, error) {
+	binNames, err := mapper.BinNames((*T)(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, len(userKeys))
+	for i, userKey := range userKeys {
+		keys[i] = r.Key(userKey)
+	}
+
+	records, err := r.store.BatchGet(ctx, keys, binNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*T, len(records))
+	for i, record := range records {
+		if record == nil {
+			continue
+		}
+		if items[i], err = r.decode(keys[i], record); err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// decode decodes the record into a new value of T. The record user key is
+// populated from the requested key, as the store may not persist user keys.
+func (r *Repository[T]) decode(key *Key, record *mapper.Record) (*T, error) {
+	if record.UserKey == nil {
+		record.UserKey = key.UserKey
+	}
+
+	item := new(T)
+	if err := r.mapper.Decode(record, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,134 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+	"github.com/reugn/aerospike-mapper-go/repository"
+)
+
+// mapStore is a simplified Store implementation keyed by the user key.
+type mapStore struct {
+	records map[any]*mapper.Record
+	intents []*mapper.WriteIntent
+}
+
+var _ repository.Store = (*mapStore)(nil)
+
+func newMapStore() *mapStore {
+	return &mapStore{records: make(map[any]*mapper.Record)}
+}
+
+func (s *mapStore) Get(_ context.Context, key *repository.Key,
+	_ ...string) (*mapper.Record, error) {
+	record, ok := s.records[key.UserKey]
+	if !ok {
+		return nil, repository.ErrRecordNotFound
+	}
+	return &mapper.Record{Key: record.Key, Metadata: record.Metadata, Bins: record.Bins}, nil
+}
+
+func (s *mapStore) Put(_ context.Context, record *mapper.Record,
+	intent *mapper.WriteIntent) error {
+	s.records[record.UserKey] = record
+	s.intents = append(s.intents, intent)
+	return nil
+}
+
+func (s *mapStore) Delete(_ context.Context, key *repository.Key) (bool, error) {
+	_, ok := s.records[key.UserKey]
+	delete(s.records, key.UserKey)
+	return ok, nil
+}
+
+func (s *mapStore) Exists(_ context.Context, key *repository.Key) (bool, error) {
+	_, ok := s.records[key.UserKey]
+	return ok, nil
+}
+
+func (s *mapStore) BatchGet(ctx context.Context, keys []*repository.Key,
+	binNames ...string) ([]*mapper.Record, error) {
+	records := make([]*mapper.Record, len(keys))
+	for i, key := range keys {
+		record, err := s.Get(ctx, key, binNames...)
+		if err == nil {
+			records[i] = record
+		}
+	}
+	return records, nil
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	store := newMapStore()
+	repo := repository.New[testtypes.User](store, "test", "users")
+
+	user := &testtypes.User{
+		KeyValue:   mapper.KeyValue{UserKey: "user1"},
+		Name:       "name1",
+		LoginCount: 2,
+	}
+	err := repo.Put(ctx, user)
+	assert.IsNil(t, err)
+	assert.Equal(t, store.intents[0].RecordExistsAction, mapper.RecordCreateOnly)
+
+	exists, err := repo.Exists(ctx, "user1")
+	assert.IsNil(t, err)
+	assert.Equal(t, exists, true)
+
+	fetched, err := repo.Get(ctx, "user1")
+	assert.IsNil(t, err)
+	assert.Equal(t, fetched.UserKey, any("user1"))
+	assert.Equal(t, fetched.Namespace, "test")
+	assert.Equal(t, fetched.SetName, "users")
+	assert.Equal(t, fetched.Name, "name1")
+	assert.Equal(t, fetched.LoginCount, 2)
+
+	_, err = repo.Get(ctx, "user2")
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound)
+	assert.ErrorIs(t, err, mapper.ErrRecordNotFound)
+
+	users, err := repo.BatchGet(ctx, "user1", "user2")
+	assert.IsNil(t, err)
+	assert.Equal(t, len(users), 2)
+	assert.Equal(t, users[0].Name, "name1")
+	assert.IsNil(t, users[1])
+
+	deleted, err := repo.Delete(ctx, "user1")
+	assert.IsNil(t, err)
+	assert.Equal(t, deleted, true)
+
+	exists, err = repo.Exists(ctx, "user1")
+	assert.IsNil(t, err)
+	assert.Equal(t, exists, false)
+}
+
+type secret struct {
+	mapper.KeyValue
+	Value string `aero:"value,encrypt"`
+}
+
+func TestRepository_WithMapper(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := mapper.NewAESCipher("k1", map[string][]byte{"k1": make([]byte, 32)})
+	assert.IsNil(t, err)
+
+	store := newMapStore()
+	repo := repository.New[secret](store, "test", "secrets",
+		repository.WithMapper(mapper.New(mapper.WithCipher(cipher))))
+
+	err = repo.Put(ctx, &secret{KeyValue: mapper.KeyValue{UserKey: "key1"}, Value: "value1"})
+	assert.IsNil(t, err)
+	_, ok := store.records["key1"].Bins["value"].([]byte)
+	assert.Equal(t, ok, true)
+
+	fetched, err := repo.Get(ctx, "key1")
+	assert.IsNil(t, err)
+	assert.Equal(t, fetched.Value, "value1")
+
+	err = repository.New[secret](store, "test", "secrets").Put(ctx, &secret{Value: "value2"})
+	assert.ErrorIs(t, err, mapper.ErrCipherNotConfigured)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+)
+
+// Key identifies a record in the Store by namespace, set name and either the
+// user key or the digest.
+type Key struct {
+	mapper.Key
+	mapper.KeyValue
+}
+
+// NewKey returns a new Key for the given namespace, set name and user key.
+func NewKey(namespace, setName string, userKey any) *Key {
+	return &Key{
+		Key: mapper.Key{
+			Namespace: namespace,
+			SetName:   setName,
+		},
+		KeyValue: mapper.KeyValue{
+			UserKey: userKey,
+		},
+	}
+}
+
+// KeyOf returns the Key of the given record.
+func KeyOf(record *mapper.Record) *Key {
+	return &Key{
+		Key:      record.Key,
+		KeyValue: record.KeyValue,
+	}
+}
+
+// Store is a minimal record storage abstraction expressed in mapper.Record terms.
+// Implementations adapt it to the Aerospike client or provide an alternative storage.
+type Store interface {
+	// Get returns the record for the given key. If binNames are specified, only those
+	// bins are read. Returns ErrRecordNotFound if the record does not exist.
+	Get(ctx context.Context, key *Key, binNames ...string) (*mapper.Record, error)
+
+	// Put writes the record bins according to the given write intent.
+	// A nil intent results in an unconditional update of the record.
+	Put(ctx context.Context, record *mapper.Record, intent *mapper.WriteIntent) error
+
+	// Delete deletes the record for the given key, and reports whether the record existed.
+	Delete(ctx context.Context, key *Key) (bool, error)
+
+	// Exists reports whether the record for the given key exists.
+	Exists(ctx context.Context, key *Key) (bool, error)
+
+	// BatchGet returns the records for the given keys in the same order.
+	// If binNames are specified, only those bins are read. The slice entries for
+	// the records that do not exist are nil.
+	BatchGet(ctx context.Context, keys []*Key, binNames ...string) ([]*mapper.Record, error)
+}
@@ -0,0 +1,193 @@
+package mapper
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// The approximate sizes of the Aerospike wire protocol elements.
+const (
+	// messageHeaderSize is the size of the protocol (8) and message (22) headers.
+	messageHeaderSize = 30
+	// fieldHeaderSize is the size of the field length (4) and field type (1).
+	fieldHeaderSize = 5
+	// binHeaderSize is the size of the operation length (4), operation type,
+	// particle type, version and bin name length (1 each).
+	binHeaderSize = 8
+	// digestSize is the size of the record key digest.
+	digestSize = 20
+	// integerSize is the size of the integer and float particles.
+	integerSize = 8
+)
+
+// RecordTooLargeError is returned by the Mapper configured using WithMaxRecordSize,
+// when the estimated size of the encoded record exceeds the limit.
+type RecordTooLargeError struct {
+	// Size is the estimated size of the record in bytes.
+	Size int
+	// Limit is the maximum record size in bytes.
+	Limit int
+}
+
+// Error returns the string representation of the error.
+func (e *RecordTooLargeError) Error() string {
+	return fmt.Sprintf("%s: estimated size %d exceeds the limit of %d bytes",
+		ErrRecordTooLarge, e.Size, e.Limit)
+}
+
+// Is reports whether the target is ErrRecordTooLarge.
+func (e *RecordTooLargeError) Is(target error) bool {
+	return target == ErrRecordTooLarge
+}
+
+// EstimateSize returns the approximate size in bytes of the write request of the
+// record, which includes the message header, the key fields, the bin names and the
+// bin values. Lists and maps are sized according to their msgpack encoding.
+// It is meant to detect the records exceeding the namespace write-block size before
+// writing them, and is not exact.
+func EstimateSize(rec *Record) int {
+	size := messageHeaderSize
+	size += fieldHeaderSize + len(rec.Namespace)
+	if rec.SetName != "" {
+		size += fieldHeaderSize + len(rec.SetName)
+	}
+	size += fieldHeaderSize + digestSize
+	if rec.UserKey != nil {
+		size += fieldHeaderSize + 1 + particleSize(reflect.ValueOf(rec.UserKey))
+	}
+
+	for binName, binValue := range rec.Bins {
+		size += binHeaderSize + len(binName) + particleSize(reflect.ValueOf(binValue))
+	}
+
+	return size
+}
+
+// checkSize returns a *RecordTooLargeError if the estimated size of the record
+// exceeds the maximum record size of the Mapper.
+func (m *Mapper) checkSize(record *Record) error {
+	if m.maxRecordSize > 0 {
+		if size := EstimateSize(record); size > m.maxRecordSize {
+			return &RecordTooLargeError{Size: size, Limit: m.maxRecordSize}
+		}
+	}
+	return nil
+}
+
+// particleSize returns the approximate size of the bin value particle.
+func particleSize(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return 0
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return 0
+		}
+		return particleSize(v.Elem())
+	case reflect.Bool:
+		return 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return integerSize
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Len()
+		}
+		return msgpackSize(v)
+	case reflect.Map:
+		return msgpackSize(v)
+	default:
+		return len(fmt.Sprint(v.Interface()))
+	}
+}
+
+// msgpackSize returns the size of the value in the msgpack based format Aerospike
+// uses to store lists and maps.
+//
+//nolint:gocyclo
+func msgpackSize(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return 1
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return 1
+		}
+		return msgpackSize(v.Elem())
+	case reflect.Bool:
+		return 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return msgpackIntSize(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return 9
+		}
+		return msgpackIntSize(int64(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return 9
+	case reflect.String:
+		// the strings are prefixed with the particle type
+		return msgpackHeaderSize(v.Len()+1, 32) + v.Len() + 1
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// the blobs are prefixed with the particle type
+			return msgpackHeaderSize(v.Len()+1, 32) + v.Len() + 1
+		}
+		if v.Type() == mapPairsType {
+			// the ordered maps are prefixed with an extension entry holding the map flags
+			size := msgpackHeaderSize(v.Len()+1, 16) + 4
+			for i := 0; i < v.Len(); i++ {
+				size += msgpackSize(v.Index(i).Field(0)) + msgpackSize(v.Index(i).Field(1))
+			}
+			return size
+		}
+		size := msgpackHeaderSize(v.Len(), 16)
+		for i := 0; i < v.Len(); i++ {
+			size += msgpackSize(v.Index(i))
+		}
+		return size
+	case reflect.Map:
+		size := msgpackHeaderSize(v.Len(), 16)
+		iter := v.MapRange()
+		for iter.Next() {
+			size += msgpackSize(iter.Key()) + msgpackSize(iter.Value())
+		}
+		return size
+	default:
+		return msgpackSize(reflect.ValueOf(fmt.Sprint(v.Interface())))
+	}
+}
+
+// msgpackIntSize returns the msgpack size of the integer.
+func msgpackIntSize(i int64) int {
+	switch {
+	case i >= -32 && i < 128:
+		return 1
+	case i >= math.MinInt8 && i <= math.MaxUint8:
+		return 2
+	case i >= math.MinInt16 && i <= math.MaxUint16:
+		return 3
+	case i >= math.MinInt32 && i <= math.MaxUint32:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// msgpackHeaderSize returns the msgpack header size of a string, list or map of the
+// given length. fixLimit is the maximum length (exclusive) of the fix format, which
+// stores the length in the type byte.
+func msgpackHeaderSize(length, fixLimit int) int {
+	switch {
+	case length < fixLimit:
+		return 1
+	case length < 1<<16:
+		return 3
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,92 @@
+package mapper_test
+
+import (
+	"strings"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_EstimateSize(t *testing.T) {
+	header := 30 + 5 + 2 + 5 + 20 // message header, namespace, digest
+
+	tests := []struct {
+		name     string
+		record   mapper.Record
+		expected int
+	}{
+		{
+			name:     "empty",
+			record:   mapper.Record{Key: mapper.Key{Namespace: "ns"}},
+			expected: header,
+		},
+		{
+			name: "key",
+			record: mapper.Record{
+				Key:      mapper.Key{Namespace: "ns", SetName: "set"},
+				KeyValue: mapper.KeyValue{UserKey: "key1"},
+			},
+			expected: header + 5 + 3 + 5 + 1 + 4,
+		},
+		{
+			name: "scalars",
+			record: mapper.Record{
+				Key: mapper.Key{Namespace: "ns"},
+				Bins: map[string]any{
+					"int":    1,
+					"float":  1.5,
+					"bool":   true,
+					"string": "value",
+					"bytes":  []byte{1, 2, 3},
+					"nil":    nil,
+				},
+			},
+			expected: header + (8 + 3 + 8) + (8 + 5 + 8) + (8 + 4 + 1) + (8 + 6 + 5) + (8 + 5 + 3) + (8 + 3 + 0),
+		},
+		{
+			name: "cdt",
+			record: mapper.Record{
+				Key: mapper.Key{Namespace: "ns"},
+				Bins: map[string]any{
+					"list": []any{1, 200, -1000, "ab", 1.5},
+					"map":  map[string]int{"a": 70000},
+				},
+			},
+			expected: header + (8 + 4 + 1 + 1 + 2 + 3 + 4 + 9) + (8 + 3 + 1 + 3 + 5),
+		},
+		{
+			name: "large",
+			record: mapper.Record{
+				Key: mapper.Key{Namespace: "ns"},
+				Bins: map[string]any{
+					"list": []string{strings.Repeat("a", 100)},
+				},
+			},
+			expected: header + (8 + 4 + 1 + 3 + 101),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, mapper.EstimateSize(&test.record), test.expected)
+		})
+	}
+}
+
+func TestMapper_EncodeMaxRecordSize(t *testing.T) {
+	document := testtypes.Document{Title: strings.Repeat("a", 1000)}
+
+	m := mapper.New(mapper.WithMaxRecordSize(1000))
+	_, err := m.Encode(&document)
+	assert.ErrorIs(t, err, mapper.ErrRecordTooLarge)
+	assert.Equal(t, err.Error(),
+		"record too large: estimated size 1088 exceeds the limit of 1000 bytes")
+
+	// the size of the compressed record is within the limit
+	m = mapper.New(mapper.WithMaxRecordSize(1000), mapper.WithCompressionThreshold(100))
+	record, err := m.Encode(&document)
+	assert.IsNil(t, err)
+	assert.Equal(t, mapper.EstimateSize(record) < 1000, true)
+}
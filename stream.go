@@ -0,0 +1,79 @@
+package mapper
+
+import (
+	"context"
+	"reflect"
+)
+
+// DecodeStream decodes the records read from the in channel, such as the results
+// of a query or a scan, into values of type T.
+//
+// The decoded values are sent to the returned values channel, and the failures to
+// the returned errors channel as *ItemError, with the index of the item in the input.
+// T can be a struct type or a pointer to a struct type. An input item can be an
+// aerospike record, a result wrapper with the Record and Err fields, or an error.
+// Both channels are closed when the in channel is closed and all the items are
+// processed, or when the context is cancelled. The channels must be consumed
+// concurrently, unless the context is cancelled.
+//
+// By default, the items are decoded sequentially by the default Mapper. Use WithWorkers
+// to decode in parallel, WithUnordered to emit the values in completion order, and
+// WithMapper to decode using a configured Mapper.
+func DecodeStream[T any](ctx context.Context, in <-chan any,
+	opts ...PipelineOption) (<-chan T, <-chan error) {
+	config := newPipelineConfig(opts)
+	values := make(chan T, config.bufferSize)
+	errs := make(chan error, config.bufferSize)
+
+	go func() {
+		defer close(values)
+		defer close(errs)
+
+		decodeItem := func(item any) (T, error) {
+			return decodeStreamItem[T](config.mapper, item)
+		}
+		runPipeline(ctx, in, config, decodeItem,
+			func(result pipelineResult[T]) bool {
+				if result.err != nil {
+					return send[error](ctx, errs, &ItemError{Index: result.index, Err: result.err})
+				}
+				return send(ctx, values, result.value)
+			})
+	}()
+
+	return values, errs
+}
+
+// decodeStreamItem decodes a single stream item into a new value of type T using
+// the Mapper m. T can be a struct or a pointer to a struct.
+func decodeStreamItem[T any](m *Mapper, item any) (T, error) {
+	var value T
+	if err, ok := item.(error); ok {
+		return value, err
+	}
+
+	// check the result wrapper error
+	itemValue := reflect.ValueOf(item)
+	if itemValue.Kind() == reflect.Ptr && !itemValue.IsNil() {
+		itemValue = itemValue.Elem()
+	}
+	if itemValue.Kind() == reflect.Struct {
+		if errValue, err := getField(itemValue, "Err"); err == nil &&
+			errValue.Kind() == reflect.Interface && !errValue.IsNil() {
+			if itemErr, ok := errValue.Interface().(error); ok {
+				return value, itemErr
+			}
+		}
+	}
+
+	// allocate the value if T is a pointer type
+	target := any(&value)
+	if valueType := reflect.TypeOf(target).Elem(); valueType.Kind() == reflect.Ptr {
+		ptrValue := reflect.New(valueType.Elem())
+		reflect.ValueOf(target).Elem().Set(ptrValue)
+		target = ptrValue.Interface()
+	}
+
+	err := m.Decode(item, target)
+	return value, err
+}
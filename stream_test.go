@@ -0,0 +1,117 @@
+package mapper_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+// result mirrors the Aerospike client query and scan result.
+type result struct {
+	Record *testtypes.Record
+	Err    error
+}
+
+func newStream(n int) <-chan any {
+	in := make(chan any)
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			key, _ := testtypes.NewKey("ns1", "set1", i)
+			switch {
+			case i%10 == 3:
+				in <- &result{Err: fmt.Errorf("error %d", i)}
+			case i%10 == 7:
+				in <- errors.New("error")
+			default:
+				in <- &result{Record: &testtypes.Record{
+					Key:  key,
+					Bins: testtypes.BinMap{"title": fmt.Sprint(i)},
+				}}
+			}
+		}
+	}()
+	return in
+}
+
+func TestMapper_DecodeStream(t *testing.T) {
+	const n = 100
+	tests := []struct {
+		name    string
+		opts    []mapper.PipelineOption
+		ordered bool
+	}{
+		{
+			name:    "sequential",
+			ordered: true,
+		},
+		{
+			name:    "parallel",
+			opts:    []mapper.PipelineOption{mapper.WithWorkers(4), mapper.WithBufferSize(8)},
+			ordered: true,
+		},
+		{
+			name: "unordered",
+			opts: []mapper.PipelineOption{mapper.WithWorkers(4), mapper.WithUnordered()},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, errs := mapper.DecodeStream[*testtypes.Item1](context.Background(),
+				newStream(n), test.opts...)
+
+			var errIndexes []int
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for err := range errs {
+					var itemErr *mapper.ItemError
+					assert.Equal(t, errors.As(err, &itemErr), true)
+					errIndexes = append(errIndexes, itemErr.Index)
+				}
+			}()
+
+			var titles []string
+			for value := range values {
+				titles = append(titles, value.Title)
+			}
+			<-done
+
+			assert.Equal(t, len(titles), n*8/10)
+			assert.Equal(t, len(errIndexes), n*2/10)
+			if !test.ordered {
+				sort.Slice(titles, func(i, j int) bool {
+					return len(titles[i]) < len(titles[j]) ||
+						len(titles[i]) == len(titles[j]) && titles[i] < titles[j]
+				})
+				sort.Ints(errIndexes)
+			}
+			assert.Equal(t, titles[:4], []string{"0", "1", "2", "4"})
+			assert.Equal(t, titles[len(titles)-1], "99")
+			assert.Equal(t, errIndexes[:3], []int{3, 7, 13})
+		})
+	}
+}
+
+func TestMapper_DecodeStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan any) // never closed
+	values, errs := mapper.DecodeStream[testtypes.Item1](ctx, in, mapper.WithWorkers(2))
+
+	in <- result{Record: &testtypes.Record{Bins: testtypes.BinMap{"title": "title1"}}}
+	value := <-values
+	assert.Equal(t, value.Title, "title1")
+
+	cancel()
+	_, ok := <-values
+	assert.Equal(t, ok, false)
+	_, ok = <-errs
+	assert.Equal(t, ok, false)
+}
@@ -0,0 +1,160 @@
+package mapper
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// serialized reports whether the field value is serialized into a single bin,
+// rather than mapped by the `aero` tags of its fields.
+func (t *tag) serialized() bool {
+	return t.json || t.gob
+}
+
+// transformed reports whether the field value is transformed on encode, and
+// transformed back on decode.
+func (t *tag) transformed() bool {
+	return t.serialized() || t.compress || t.encrypt
+}
+
+// encodeValue transforms the field value according to the tag options.
+// The value is serialized first, then compressed and encrypted. Values that are not
+// serialized must be of string or []byte type.
+func (m *Mapper) encodeValue(t *tag, value any) (any, error) {
+	sourceValue := reflect.ValueOf(value)
+	if sourceValue.Kind() == reflect.Ptr && sourceValue.IsNil() {
+		return nil, nil
+	}
+
+	data, err := serialize(t, sourceValue)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := false
+	if t.compress {
+		if data, compressed, err = m.compress(data); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case t.encrypt:
+		return m.encrypt(data)
+	case compressed, t.gob:
+		return data, nil
+	case t.json:
+		return string(data), nil
+	default:
+		return value, nil // keep the original value
+	}
+}
+
+// serialize returns the bytes of the field value. The value is serialized if the
+// tag specifies a serialization format, otherwise it must be a string or []byte.
+func serialize(t *tag, v reflect.Value) ([]byte, error) {
+	switch {
+	case t.json:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("json: %w", err)
+		}
+		return data, nil
+	case t.gob:
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).EncodeValue(v); err != nil {
+			return nil, fmt.Errorf("gob: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		return []byte(v.String()), nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		if v.IsNil() {
+			return []byte{}, nil
+		}
+		return v.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("cannot transform %s: string or []byte required", v.Type())
+	}
+}
+
+// decodeValue reverses the transformations of encodeValue. The value is decrypted
+// and decompressed, and then deserialized into a value of the target type, or
+// returned as a string or []byte depending on the target type. Only the values of
+// the fields tagged with `compress` are decompressed.
+func (m *Mapper) decodeValue(t *tag, binValue reflect.Value, targetType reflect.Type) (reflect.Value, error) {
+	if binValue.Kind() == reflect.Interface {
+		binValue = binValue.Elem()
+	}
+	if !binValue.IsValid() {
+		return binValue, nil // nil bin value
+	}
+
+	var data []byte
+	switch {
+	case binValue.Kind() == reflect.Slice && binValue.Type().Elem().Kind() == reflect.Uint8:
+		data = binValue.Bytes()
+	case binValue.Kind() == reflect.String && t.serialized() && !t.encrypt:
+		data = []byte(binValue.String())
+	case t.encrypt:
+		return reflect.Value{}, fmt.Errorf("%w: %s bin value", ErrInvalidCiphertext,
+			binValue.Type())
+	default:
+		return binValue, nil // not a transformed value
+	}
+
+	var err error
+	if t.encrypt {
+		if data, err = m.decrypt(data); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if t.compress {
+		if data, err = m.decompress(data); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if t.serialized() {
+		return deserialize(t, data, targetType)
+	}
+
+	if targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	switch {
+	case targetType.Kind() == reflect.String:
+		return reflect.ValueOf(string(data)).Convert(targetType), nil
+	case targetType.Kind() == reflect.Slice && targetType.Elem().Kind() == reflect.Uint8:
+		return reflect.ValueOf(data).Convert(targetType), nil
+	default:
+		return reflect.ValueOf(data), nil
+	}
+}
+
+// deserialize decodes the serialized data into a new value of the target type.
+func deserialize(t *tag, data []byte, targetType reflect.Type) (reflect.Value, error) {
+	target := reflect.New(targetType)
+	switch {
+	case t.json:
+		if err := json.Unmarshal(data, target.Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("json: %w", err)
+		}
+	case t.gob:
+		if err := gob.NewDecoder(bytes.NewReader(data)).DecodeValue(target); err != nil {
+			return reflect.Value{}, fmt.Errorf("gob: %w", err)
+		}
+	}
+
+	return target.Elem(), nil
+}
@@ -0,0 +1,66 @@
+package mapper_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_EncodeSerialized(t *testing.T) {
+	workflow := testtypes.Workflow{
+		ID:      "id1",
+		State:   testtypes.State{Step: 2, Labels: map[string]string{"a": "b"}},
+		History: []testtypes.State{{Step: 1}},
+		Current: &testtypes.State{Step: 2, Labels: map[string]string{
+			"description": strings.Repeat("description", 100),
+		}},
+	}
+
+	record, err := mapper.Encode(&workflow)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["id"], any("id1"))
+	assert.Equal(t, record.Bins["state"], any(`{"step":2,"labels":{"a":"b"}}`))
+	_, ok := record.Bins["history"].([]byte)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, record.Bins["current"].([]byte)[3], mapper.CodecGzip)
+
+	binNames, err := mapper.BinNames(&workflow)
+	assert.IsNil(t, err)
+	assert.Equal(t, binNames, []string{"id", "state", "history", "current"})
+
+	var decoded testtypes.Workflow
+	err = mapper.Decode(record, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, workflow)
+
+	// nil pointers are encoded as nil bins
+	workflow.Current = nil
+	record, err = mapper.Encode(&workflow)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["current"], nil)
+
+	decoded = testtypes.Workflow{}
+	err = mapper.Decode(record, &decoded)
+	assert.IsNil(t, err)
+	assert.Equal(t, decoded, workflow)
+}
+
+func TestMapper_DecodeSerializedError(t *testing.T) {
+	var decoded testtypes.Workflow
+	err := mapper.Decode(&testtypes.Record{
+		Bins: testtypes.BinMap{"state": "{invalid"},
+	}, &decoded)
+	var syntaxErr *json.SyntaxError
+	assert.Equal(t, errors.As(err, &syntaxErr), true)
+
+	_, err = mapper.Encode(&struct {
+		Value chan int `aero:"value,json"`
+	}{Value: make(chan int)})
+	var typeErr *json.UnsupportedTypeError
+	assert.Equal(t, errors.As(err, &typeErr), true)
+}
@@ -0,0 +1,347 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	ruleMin      = "min="
+	ruleMax      = "max="
+	ruleLen      = "len="
+	ruleLenLess  = "len<="
+	ruleLenMore  = "len>="
+	ruleOneOf    = "oneof="
+	ruleRegex    = "regex="
+	oneOfDivider = "|"
+)
+
+// rulePrefixes lists the validation rule prefixes of the `aero` tag.
+var rulePrefixes = []string{ruleMin, ruleMax, ruleLen, ruleLenLess, ruleLenMore,
+	ruleOneOf, ruleRegex}
+
+// isRule reports whether the tag part is a validation rule.
+func isRule(part string) bool {
+	for _, prefix := range rulePrefixes {
+		if strings.HasPrefix(part, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrValidationFailed is matched by the ValidationError using errors.Is.
+var ErrValidationFailed = errors.New("validation failed")
+
+// FieldError describes a validation rule violation of a struct field.
+type FieldError struct {
+	// Field is the path of the struct field, e.g. "Address.City".
+	Field string
+	// BinName is the name of the bin the field is mapped to.
+	BinName string
+	// Rule is the violated rule as specified in the tag, e.g. "max=150".
+	Rule string
+	// Value is the field value.
+	Value any
+}
+
+// Error returns the string representation of the error.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s (bin %q): value %v violates %s",
+		e.Field, e.BinName, e.Value, e.Rule)
+}
+
+// ValidationError is returned when the field values violate the validation rules
+// specified in the `aero` tags. It contains all the violations.
+type ValidationError struct {
+	// Errors contains the field errors in the order of the struct fields.
+	Errors []*FieldError
+}
+
+// Error returns the string representation of the error.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrValidationFailed, strings.Join(messages, "; "))
+}
+
+// Is reports whether the target is ErrValidationFailed.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// rule is a compiled validation rule of a struct field.
+type rule struct {
+	// text is the rule as specified in the tag.
+	text string
+	// check reports whether the dereferenced field value satisfies the rule.
+	check func(reflect.Value) bool
+}
+
+// compileRule compiles the rule text for the field type t.
+func compileRule(text string, t reflect.Type) (rule, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	compiled := rule{text: text}
+	notApplicable := fmt.Errorf("%w: rule %s is not applicable to %s", ErrInvalidTag, text, t)
+	switch {
+	case strings.HasPrefix(text, ruleMin), strings.HasPrefix(text, ruleMax):
+		if !isNumericKind(t.Kind()) {
+			return rule{}, notApplicable
+		}
+		limit, err := strconv.ParseFloat(text[len(ruleMin):], 64)
+		if err != nil {
+			return rule{}, fmt.Errorf("%w: invalid rule %s: %v", ErrInvalidTag, text, err)
+		}
+		if strings.HasPrefix(text, ruleMin) {
+			compiled.check = func(v reflect.Value) bool { return numericValue(v) >= limit }
+		} else {
+			compiled.check = func(v reflect.Value) bool { return numericValue(v) <= limit }
+		}
+
+	case strings.HasPrefix(text, ruleLenLess), strings.HasPrefix(text, ruleLenMore),
+		strings.HasPrefix(text, ruleLen):
+		if !hasLength(t.Kind()) {
+			return rule{}, notApplicable
+		}
+		var limitText string
+		var compare func(length, limit int) bool
+		switch {
+		case strings.HasPrefix(text, ruleLenLess):
+			limitText = text[len(ruleLenLess):]
+			compare = func(length, limit int) bool { return length <= limit }
+		case strings.HasPrefix(text, ruleLenMore):
+			limitText = text[len(ruleLenMore):]
+			compare = func(length, limit int) bool { return length >= limit }
+		default:
+			limitText = text[len(ruleLen):]
+			compare = func(length, limit int) bool { return length == limit }
+		}
+		limit, err := strconv.Atoi(limitText)
+		if err != nil {
+			return rule{}, fmt.Errorf("%w: invalid rule %s: %v", ErrInvalidTag, text, err)
+		}
+		compiled.check = func(v reflect.Value) bool { return compare(valueLength(v), limit) }
+
+	case strings.HasPrefix(text, ruleOneOf):
+		if t.Kind() != reflect.String && !isIntegerKind(t.Kind()) {
+			return rule{}, notApplicable
+		}
+		allowed := make(map[string]struct{})
+		for _, option := range strings.Split(text[len(ruleOneOf):], oneOfDivider) {
+			allowed[option] = struct{}{}
+		}
+		compiled.check = func(v reflect.Value) bool {
+			_, ok := allowed[formatValue(v)]
+			return ok
+		}
+
+	case strings.HasPrefix(text, ruleRegex):
+		if t.Kind() != reflect.String {
+			return rule{}, notApplicable
+		}
+		re, err := regexp.Compile(text[len(ruleRegex):])
+		if err != nil {
+			return rule{}, fmt.Errorf("%w: invalid rule %s: %v", ErrInvalidTag, text, err)
+		}
+		compiled.check = func(v reflect.Value) bool { return re.MatchString(v.String()) }
+
+	default:
+		return rule{}, fmt.Errorf("%w: unknown rule %s", ErrInvalidTag, text)
+	}
+
+	return compiled, nil
+}
+
+// isNumericKind reports whether k is an integer or a floating-point kind.
+func isNumericKind(k reflect.Kind) bool {
+	return isIntegerKind(k) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// hasLength reports whether the values of kind k have a length.
+func hasLength(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// numericValue returns the numeric value v as a float64.
+func numericValue(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+// valueLength returns the length of v, using the number of runes for strings.
+func valueLength(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
+
+// formatValue returns the string representation of the string or integer value v.
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10)
+	default:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+}
+
+// rulesCache caches whether the struct types, or any of their nested structs,
+// have fields with validation rules.
+var rulesCache sync.Map // map[reflect.Type]bool
+
+// Validate checks the field values of v against the validation rules specified in
+// the `aero` tags, e.g. `aero:"age,min=0,max=150"`.
+// v must be a struct or struct pointer. Returns a *ValidationError containing all
+// the violations, if any. Nil pointers and the empty values of the omitempty and
+// nullable fields are not validated.
+//
+// Validate is called by Encode. Call it after Decode to validate the decoded values.
+func Validate(v any) error {
+	value, err := structValue(v)
+	if err != nil {
+		return err
+	}
+
+	hasRules, err := typeHasRules(value.Type())
+	if err != nil || !hasRules {
+		return err
+	}
+
+	var fieldErrors []*FieldError
+	if err := validateStruct(value, "", &fieldErrors); err != nil {
+		return err
+	}
+	if len(fieldErrors) > 0 {
+		return &ValidationError{Errors: fieldErrors}
+	}
+
+	return nil
+}
+
+// typeHasRules reports whether the struct type t, or any of its nested structs,
+// has fields with validation rules.
+func typeHasRules(t reflect.Type) (bool, error) {
+	if cached, ok := rulesCache.Load(t); ok {
+		return cached.(bool), nil
+	}
+
+	result, err := findRules(t, make(map[reflect.Type]struct{}))
+	if err != nil {
+		return false, err
+	}
+	rulesCache.Store(t, result)
+	return result, nil
+}
+
+// findRules walks the struct type t, guarding against recursive types.
+func findRules(t reflect.Type, visited map[reflect.Type]struct{}) (bool, error) {
+	if _, ok := visited[t]; ok {
+		return false, nil
+	}
+	visited[t] = struct{}{}
+
+	fields, err := cachedFields(t)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range fields {
+		if len(fields[i].rules) > 0 {
+			return true, nil
+		}
+		fieldType := t.Field(i).Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType.String() != timeType {
+			found, err := findRules(fieldType, visited)
+			if found || err != nil {
+				return found, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// validateStruct recursively validates the struct value and collects the field errors.
+func validateStruct(value reflect.Value, path string, fieldErrors *[]*FieldError) error {
+	fields, err := cachedFields(value.Type())
+	if err != nil {
+		return err
+	}
+
+	for i := range fields {
+		structField := value.Type().Field(i)
+		fieldPath := structField.Name
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		fieldValue := fieldValueDeref(value, i)
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type().String() != timeType {
+			if structField.Anonymous {
+				fieldPath = path
+			}
+			if err := validateStruct(fieldValue, fieldPath, fieldErrors); err != nil {
+				return err
+			}
+			continue
+		}
+
+		field := &fields[i]
+		if len(field.rules) == 0 || !fieldValue.IsValid() {
+			continue
+		}
+		if (field.tag.omitempty || field.tag.nullable) && isEmptyValue(fieldValue) {
+			continue
+		}
+
+		for _, fieldRule := range field.rules {
+			if !fieldRule.check(fieldValue) {
+				*fieldErrors = append(*fieldErrors, &FieldError{
+					Field:   fieldPath,
+					BinName: field.tag.name,
+					Rule:    fieldRule.text,
+					Value:   valueOf(fieldValue),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// valueOf returns the interface value of v, if it can be obtained
+// without panicking on unexported fields.
+func valueOf(v reflect.Value) any {
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return nil
+}
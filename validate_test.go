@@ -0,0 +1,100 @@
+package mapper_test
+
+import (
+	"errors"
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_Validate(t *testing.T) {
+	level := uint8(1)
+	valid := testtypes.Person{
+		Name:     "name1",
+		Age:      30,
+		Role:     "admin",
+		Level:    &level,
+		Tags:     []string{"a", "b"},
+		Location: testtypes.Location{Zip: "12345"},
+	}
+	assert.IsNil(t, mapper.Validate(&valid))
+
+	record, err := mapper.Encode(&valid)
+	assert.IsNil(t, err)
+	assert.Equal(t, record.Bins["name"], any("name1"))
+
+	level = 0
+	invalid := testtypes.Person{
+		Name:     "long name",
+		Age:      151,
+		Role:     "guest",
+		Code:     "abc",
+		Level:    &level,
+		Tags:     []string{"a", "b", "c"},
+		Location: testtypes.Location{Zip: "1234"},
+	}
+	err = mapper.Validate(invalid)
+	assert.ErrorIs(t, err, mapper.ErrValidationFailed)
+
+	var validationErr *mapper.ValidationError
+	assert.Equal(t, errors.As(err, &validationErr), true)
+	assert.Equal(t, validationErr.Errors, []*mapper.FieldError{
+		{Field: "Name", BinName: "name", Rule: "len<=8", Value: "long name"},
+		{Field: "Age", BinName: "age", Rule: "max=150", Value: 151},
+		{Field: "Role", BinName: "role", Rule: "oneof=admin|user", Value: "guest"},
+		{Field: "Code", BinName: "code", Rule: "regex=^[A-Z]{3}$", Value: "abc"},
+		{Field: "Level", BinName: "level", Rule: "min=1", Value: uint8(0)},
+		{Field: "Tags", BinName: "tags", Rule: "len<=2", Value: []string{"a", "b", "c"}},
+		{Field: "Location.Zip", BinName: "zip", Rule: "len=5", Value: "1234"},
+	})
+	assert.Equal(t, validationErr.Errors[1].Error(),
+		`field Age (bin "age"): value 151 violates max=150`)
+
+	_, err = mapper.Encode(&invalid)
+	assert.ErrorIs(t, err, mapper.ErrValidationFailed)
+}
+
+func TestMapper_ValidateSkipsEmpty(t *testing.T) {
+	// nil pointers and empty omitempty fields are not validated
+	err := mapper.Validate(&testtypes.Person{Name: "name1", Role: "user",
+		Location: testtypes.Location{Zip: "12345"}})
+	assert.IsNil(t, err)
+
+	// the rules apply to the empty values of the other fields
+	err = mapper.Validate(&testtypes.Person{Role: "user",
+		Location: testtypes.Location{Zip: "12345"}})
+	assert.ErrorIs(t, err, mapper.ErrValidationFailed)
+}
+
+func TestMapper_ValidateInvalidRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"minString", &struct {
+			Name string `aero:"name,min=1"`
+		}{}},
+		{"lenInt", &struct {
+			Age int `aero:"age,len<=1"`
+		}{}},
+		{"invalidLimit", &struct {
+			Age int `aero:"age,max=abc"`
+		}{}},
+		{"invalidRegex", &struct {
+			Name string `aero:"name,regex=[a-"`
+		}{}},
+		{"oneofFloat", &struct {
+			Value float64 `aero:"value,oneof=1|2"`
+		}{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := mapper.Validate(test.value)
+			assert.ErrorIs(t, err, mapper.ErrInvalidTag)
+			assert.Equal(t, errors.Is(err, mapper.ErrValidationFailed), false)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package mapper
+
+import (
+	"reflect"
+)
+
+// GenerationPolicy determines how the record generation is checked on write.
+// The values follow the order of the Aerospike client GenerationPolicy constants.
+type GenerationPolicy int
+
+const (
+	// GenerationNone does not use the record generation to restrict writes.
+	GenerationNone GenerationPolicy = iota
+	// GenerationExpectEqual updates the record only if the expected generation is
+	// equal to the server generation.
+	GenerationExpectEqual
+	// GenerationExpectGreater updates the record only if the expected generation is
+	// greater than the server generation.
+	GenerationExpectGreater
+)
+
+// RecordExistsAction determines how to handle writes when the record already exists.
+// The values follow the order of the Aerospike client RecordExistsAction constants.
+type RecordExistsAction int
+
+const (
+	// RecordUpdate creates the record if it does not exist, or merges the bins
+	// with the existing record.
+	RecordUpdate RecordExistsAction = iota
+	// RecordUpdateOnly merges the bins with the existing record, and fails if the
+	// record does not exist.
+	RecordUpdateOnly
+	// RecordReplace creates the record if it does not exist, or replaces all the bins
+	// of the existing record.
+	RecordReplace
+	// RecordReplaceOnly replaces all the bins of the existing record, and fails if the
+	// record does not exist.
+	RecordReplaceOnly
+	// RecordCreateOnly creates the record, and fails if the record already exists.
+	RecordCreateOnly
+)
+
+// WriteIntent describes the write conditions of an encoded record.
+// It is used by the storage layer to build the write policy.
+type WriteIntent struct {
+	// Generation is the expected generation of the record.
+	Generation uint32
+	// GenerationPolicy determines how the expected generation is checked.
+	GenerationPolicy GenerationPolicy
+	// RecordExistsAction determines how to handle the write if the record exists.
+	RecordExistsAction RecordExistsAction
+}
+
+// EncodeForUpdate encodes v into a Record and returns the write intent derived from
+// the `meta,generation` field of v.
+//
+// If v has a non-zero generation, the record is expected to exist and to have the
+// same generation on the server (compare-and-set). A zero generation means the
+// record was not read before, so the write succeeds only if the record does not exist.
+// If v does not map the generation at all, the write is unconditional.
+func EncodeForUpdate(v any) (*Record, *WriteIntent, error) {
+	return defaultMapper.EncodeForUpdate(v)
+}
+
+// EncodeForUpdate encodes v into a Record using the Mapper configuration, and returns
+// the write intent. See the package level EncodeForUpdate function for details.
+func (m *Mapper) EncodeForUpdate(v any) (*Record, *WriteIntent, error) {
+	record, err := m.Encode(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	intent := &WriteIntent{
+		GenerationPolicy:   GenerationNone,
+		RecordExistsAction: RecordUpdate,
+	}
+
+	switch {
+	case record.Generation > 0:
+		intent.Generation = record.Generation
+		intent.GenerationPolicy = GenerationExpectEqual
+		intent.RecordExistsAction = RecordUpdateOnly
+	case hasMetaTag(reflect.TypeOf(v), metaTagGeneration):
+		intent.RecordExistsAction = RecordCreateOnly
+	}
+
+	return record, intent, nil
+}
+
+// hasMetaTag reports whether the struct type t, or any of its nested structs,
+// contains a field tagged with the given metadata tag value.
+func hasMetaTag(t reflect.Type, name string) bool {
+	if t == nil {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType.String() != timeType {
+			if hasMetaTag(fieldType, name) {
+				return true
+			}
+			continue
+		}
+
+		aeroTag := field.Tag.Get(mapperTag)
+		if aeroTag == "" {
+			continue
+		}
+
+		tag, err := parseTag(aeroTag)
+		if err == nil && tag.meta && tag.name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package mapper_test
+
+import (
+	"testing"
+
+	mapper "github.com/reugn/aerospike-mapper-go"
+	"github.com/reugn/aerospike-mapper-go/internal/assert"
+	"github.com/reugn/aerospike-mapper-go/internal/testtypes"
+)
+
+func TestMapper_EncodeForUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected mapper.WriteIntent
+	}{
+		{
+			name: "generation",
+			value: &testtypes.Item{
+				Metadata: mapper.Metadata{Generation: 5},
+			},
+			expected: mapper.WriteIntent{
+				Generation:         5,
+				GenerationPolicy:   mapper.GenerationExpectEqual,
+				RecordExistsAction: mapper.RecordUpdateOnly,
+			},
+		},
+		{
+			name:  "zeroGeneration",
+			value: testtypes.Item{},
+			expected: mapper.WriteIntent{
+				GenerationPolicy:   mapper.GenerationNone,
+				RecordExistsAction: mapper.RecordCreateOnly,
+			},
+		},
+		{
+			name:  "noGeneration",
+			value: &testtypes.Item1{Title: "title1"},
+			expected: mapper.WriteIntent{
+				GenerationPolicy:   mapper.GenerationNone,
+				RecordExistsAction: mapper.RecordUpdate,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record, intent, err := mapper.EncodeForUpdate(test.value)
+			assert.IsNil(t, err)
+			assert.Equal(t, record.Generation, test.expected.Generation)
+			assert.Equal(t, *intent, test.expected)
+		})
+	}
+}